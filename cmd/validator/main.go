@@ -38,5 +38,8 @@ func main() {
 		fmt.Printf("There are validation errors: %v\n", err)
 		os.Exit(1)
 	}
+	for _, w := range spec.Warnings() {
+		fmt.Printf("Warning: %s\n", w)
+	}
 	fmt.Println("Config is valid")
 }
@@ -5,9 +5,10 @@ go 1.21
 require (
 	github.com/caarlos0/env/v6 v6.10.1
 	github.com/g4s8/go-matchers v0.0.0-20201209072131-8aaefc3fcb9c
-	github.com/go-telegram-bot-api/telegram-bot-api/v5 v5.5.1
+	github.com/go-telegram-bot-api/telegram-bot-api/v5 v5.5.2-0.20221020003552-4126fa611266
 	github.com/lib/pq v1.10.9
 	github.com/pkg/errors v0.9.1
+	github.com/robfig/cron/v3 v3.0.1
 	github.com/rs/zerolog v1.31.0
 	github.com/stretchr/testify v1.8.4
 	go.uber.org/multierr v1.11.0
@@ -4,6 +4,7 @@ import (
 	"github.com/g4s8/openbots/internal/bot/filters"
 	"github.com/g4s8/openbots/pkg/spec"
 	"github.com/g4s8/openbots/pkg/types"
+	telegram "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"github.com/rs/zerolog"
 )
 
@@ -15,17 +16,92 @@ func NewPostcheckoutFilter(s *spec.PostCheckoutTrigger) *filters.PostCheckout {
 	return filters.NewPostCheckout(s.InvoicePayload)
 }
 
+func NewChatMemberFilter(my bool, s *spec.ChatMemberTrigger) *filters.ChatMember {
+	return filters.NewChatMember(my, s.Status, s.OldStatus)
+}
+
+func NewSenderFilter(api *telegram.BotAPI, s *spec.SenderCondition) *filters.Sender {
+	if !s.Admin {
+		api = nil
+	}
+	return filters.NewSender(s.IDs, s.Usernames, api)
+}
+
 func NewStateFilter(sp types.StateProvider, logger zerolog.Logger, s []spec.StateCondition) filters.FilterChain {
 	logger = logger.With().Str("component", "envet_filter").Str("filter", "state_filter").Logger()
 	chain := make(filters.FilterChain, len(s))
 	for i, c := range s {
+		// present, eq and neq are exclusive in this order, other
+		// comparisons can't be combined with them by spec validation
 		if c.Present.Valid {
 			chain[i] = filters.NewStateFilterWithPresent(sp, logger, c.Key, c.Present.Value)
 		} else if c.Eq != "" {
 			chain[i] = filters.NewStateFilterEq(sp, logger, c.Key, c.Eq)
 		} else if c.NEq != "" {
 			chain[i] = filters.NewStateFilterNeq(sp, logger, c.Key, c.NEq)
+		} else {
+			chain[i] = filters.NewStateFilterCompare(sp, logger, c.Key, filters.CompareAll(comparisons(&c.Comparison)...))
 		}
 	}
 	return chain
 }
+
+// NewConditionFilter creates filter chain of value conditions.
+func NewConditionFilter(s []spec.Condition) filters.FilterChain {
+	chain := make(filters.FilterChain, len(s))
+	for i, c := range s {
+		chain[i] = filters.NewCondition(c.Value, filters.CompareAll(comparisons(&c.Comparison)...))
+	}
+	return chain
+}
+
+func comparisons(c *spec.Comparison) []filters.Compare {
+	var res []filters.Compare
+	if c.Eq != "" {
+		res = append(res, filters.CompareEq(c.Eq))
+	}
+	if c.NEq != "" {
+		res = append(res, filters.CompareNeq(c.NEq))
+	}
+	if c.Gt != "" {
+		res = append(res, filters.CompareGt(c.Gt))
+	}
+	if c.Lt != "" {
+		res = append(res, filters.CompareLt(c.Lt))
+	}
+	if c.Gte != "" {
+		res = append(res, filters.CompareGte(c.Gte))
+	}
+	if c.Lte != "" {
+		res = append(res, filters.CompareLte(c.Lte))
+	}
+	if len(c.In) > 0 {
+		res = append(res, filters.CompareIn(c.In))
+	}
+	if c.Matches != nil {
+		res = append(res, filters.CompareMatches(c.Matches.Regexp))
+	}
+	return res
+}
+
+func NewInlineQueryFilter(s *spec.InlineTrigger) *filters.InlineQuery {
+	if s.Pattern == nil {
+		return filters.NewInlineQuery(nil)
+	}
+	return filters.NewInlineQuery(s.Pattern.Regexp)
+}
+
+func NewChosenInlineResultFilter(s *spec.InlineTrigger) *filters.ChosenInlineResult {
+	if s.Pattern == nil {
+		return filters.NewChosenInlineResult(nil)
+	}
+	return filters.NewChosenInlineResult(s.Pattern.Regexp)
+}
+
+func NewPollAnswerFilter(s *spec.PollAnswerTrigger) *filters.PollAnswer {
+	var correct *bool
+	if s.Correct.Valid {
+		correct = &s.Correct.Value
+	}
+	return filters.NewPollAnswer(s.Poll, correct)
+}
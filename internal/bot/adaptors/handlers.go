@@ -3,6 +3,8 @@ package adaptors
 import (
 	"context"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/g4s8/openbots/internal/bot/handlers"
 	"github.com/g4s8/openbots/pkg/spec"
@@ -19,12 +21,31 @@ func MessageRepply(bot *telegram.BotAPI,
 ) (*handlers.MessageReply, error) {
 	var modifiers []handlers.MessageModifier
 	if s.Markup != nil && len(s.Markup.Keyboard) > 0 {
-		modifiers = append(modifiers, handlers.MessageWithKeyboard(s.Markup.Keyboard))
+		modifiers = append(modifiers, handlers.MessageWithKeyboard(keyboardFromSpec(s.Markup.Keyboard),
+			handlers.KeyboardOptions{
+				OneTime:     s.Markup.OneTime,
+				Resize:      s.Markup.ResizeKeyboard(),
+				Placeholder: s.Markup.Placeholder,
+				Selective:   s.Markup.Selective,
+			}))
+	}
+	if s.Markup != nil && s.Markup.RemoveKeyboard {
+		modifiers = append(modifiers, handlers.MessageWithRemoveKeyboard(s.Markup.Selective))
+	}
+	if s.Markup != nil && s.Markup.ForceReply {
+		modifiers = append(modifiers, handlers.MessageWithForceReply(s.Markup.Placeholder, s.Markup.Selective))
 	}
 	if s.Markup != nil && len(s.Markup.InlineKeyboard) > 0 {
 		modifiers = append(modifiers, handlers.MessageWithInlineKeyboard(
 			inlineKeyboardFromSpec(s.Markup.InlineKeyboard)))
 	}
+	if s.Markup != nil && s.Markup.InlineForeach != nil {
+		modifiers = append(modifiers, handlers.MessageWithInlineKeyboardForeach(handlers.InlineKeyboardForeach{
+			Foreach: s.Markup.InlineForeach.Foreach,
+			Button:  inlineKeyboardFromSpec([][]spec.InlineButton{{s.Markup.InlineForeach.Button}})[0][0],
+			Columns: s.Markup.InlineForeach.Columns,
+		}))
+	}
 	if s.ParseMode != "" {
 		modifiers = append(modifiers, handlers.MessageWithParseMode(string(s.ParseMode)))
 	}
@@ -58,6 +79,33 @@ func inlineKeyboardFromSpec(bts [][]spec.InlineButton) (res handlers.InlineKeybo
 			res[i][j].Text = btn.Text
 			res[i][j].URL = btn.URL
 			res[i][j].Callback = btn.Callback
+			res[i][j].WebApp = btn.WebApp
+		}
+	}
+	return
+}
+
+func keyboardFromSpec(bts [][]spec.KeyboardButton) (res handlers.Keyboard) {
+	res = make(handlers.Keyboard, len(bts))
+	for i, row := range bts {
+		res[i] = make([]handlers.KeyboardButton, len(row))
+		for j, btn := range row {
+			res[i][j].Text = btn.Text
+			res[i][j].WebApp = btn.WebApp
+			res[i][j].RequestContact = btn.RequestContact
+			res[i][j].RequestLocation = btn.RequestLocation
+			res[i][j].RequestPoll = btn.RequestPoll
+			if r := btn.RequestUsers; r != nil {
+				res[i][j].RequestUsers = &handlers.KeyboardRequestUsers{
+					RequestID: r.ID, UserIsBot: r.Bot, UserIsPremium: r.Premium, MaxQuantity: r.Max,
+				}
+			}
+			if r := btn.RequestChat; r != nil {
+				res[i][j].RequestChat = &handlers.KeyboardRequestChat{
+					RequestID: r.ID, ChatIsChannel: r.Channel, ChatIsForum: r.Forum,
+					ChatHasUsername: r.Username, ChatIsCreated: r.Created, BotIsMember: r.BotMember,
+				}
+			}
 		}
 	}
 	return
@@ -80,11 +128,20 @@ func (h *multiHandler) Handle(ctx context.Context, upd *telegram.Update, bot *te
 	return nil
 }
 
+// Replies creates handler of replies, adminChat is a default target chat of forward and copy replies.
 func Replies(bot *telegram.BotAPI, sp types.StateProvider, secrets types.Secrets, assets types.Assets, payments types.PaymentProviders,
-	r []*spec.Reply, log zerolog.Logger,
+	jobs types.JobsProvider, polls types.PollsProvider, adminChat string, r []*spec.Reply, log zerolog.Logger,
 ) (types.Handler, error) {
 	var handlers []types.Handler
 	for _, reply := range r {
+		start := len(handlers)
+		if reply.Action() != "" || reply.Pause != "" {
+			h, err := newChatAction(reply, log)
+			if err != nil {
+				return nil, errors.Wrap(err, "create chat action handler")
+			}
+			handlers = append(handlers, h)
+		}
 		if reply.Message != nil {
 			h, err := MessageRepply(bot, sp, secrets, reply.Message, log)
 			if err != nil {
@@ -102,25 +159,71 @@ func Replies(bot *telegram.BotAPI, sp types.StateProvider, secrets types.Secrets
 			}
 			handlers = append(handlers, h)
 		}
-		if reply.Delete {
-			handlers = append(handlers, newDelete(log))
-		}
-		if reply.Image != nil {
-			handlers = append(handlers, newImageReply(reply.Image, assets, log))
-		}
-		if reply.Document != nil {
-			handlers = append(handlers, newDocumentReply(reply.Document, assets, log))
-		}
+		handlers = append(handlers, newModeration(reply, log)...)
+		handlers = append(handlers, newMediaReplies(reply, assets, log)...)
 		if reply.Invoice != nil {
 			handlers = append(handlers, newInvoice(reply.Invoice, payments, sp, secrets, log))
 		}
 		if reply.PreCheckout != nil {
 			handlers = append(handlers, newPreCheckoutAnswer(reply.PreCheckout, log))
 		}
+		if reply.InlineResults != nil {
+			handlers = append(handlers, newInlineResults(reply.InlineResults, log))
+		}
+		if reply.ApproveJoinRequest {
+			handlers = append(handlers, newJoinRequestAnswer(true, log))
+		}
+		if reply.DeclineJoinRequest {
+			handlers = append(handlers, newJoinRequestAnswer(false, log))
+		}
+		if reply.Delay != nil {
+			handlers = append(handlers, newDelay(reply.Delay, jobs, log))
+		}
+		if reply.Poll != nil {
+			handlers = append(handlers, newPoll(reply.Poll, polls, log))
+		}
+		if reply.Forward != nil {
+			handlers = append(handlers, newForward(reply.Forward, false, adminChat, log))
+		}
+		if reply.Copy != nil {
+			handlers = append(handlers, newForward(reply.Copy, true, adminChat, log))
+		}
+		if reply.Paginate != nil {
+			handlers = append(handlers, NewPaginate(reply.Paginate, log))
+		}
+		if reply.SendOptions != (spec.SendOptions{}) {
+			withSendOptions(handlers[start:], reply.SendOptions)
+		}
 	}
 	return &multiHandler{handlers}, nil
 }
 
+func newChatAction(reply *spec.Reply, log zerolog.Logger) (types.Handler, error) {
+	var pause time.Duration
+	if reply.Pause != "" {
+		d, err := time.ParseDuration(reply.Pause)
+		if err != nil {
+			return nil, errors.Wrap(err, "parse pause duration")
+		}
+		pause = d
+	}
+	return handlers.NewChatAction(reply.Action(), pause, log), nil
+}
+
+// withSendOptions wraps reply handlers to send messages with options.
+func withSendOptions(hs []types.Handler, s spec.SendOptions) {
+	opts := handlers.SendOptions{
+		ReplyTo:             s.ReplyTo,
+		DisableNotification: s.DisableNotification,
+		ProtectContent:      s.ProtectContent,
+		DisableLinkPreview:  s.DisableLinkPreview,
+		ThreadID:            s.ThreadID,
+	}
+	for i, h := range hs {
+		hs[i] = handlers.WithSendOptions(h, opts)
+	}
+}
+
 func Webhook(s *spec.Webhook, cli *http.Client, sp types.StateProvider, secrets types.Secrets, log zerolog.Logger) types.Handler {
 	return handlers.NewWebhook(s.URL, cli, s.Method, s.Headers, s.Data, sp, secrets, log)
 }
@@ -150,26 +253,108 @@ func newEdit(s *spec.Edit, sp types.StateProvider, secrets types.Secrets, log ze
 		sp, secrets, log), nil
 }
 
-func newDelete(logger zerolog.Logger) types.Handler {
-	return handlers.NewMessageDelete(logger)
+// newModeration creates handlers of chat moderation replies.
+func newModeration(r *spec.Reply, logger zerolog.Logger) []types.Handler {
+	var res []types.Handler
+	if d := r.Delete; d != nil {
+		res = append(res, handlers.NewDelete(d.Target == spec.TargetReply, logger))
+	}
+	if p := r.Pin; p != nil {
+		res = append(res, handlers.NewPin(p.Target == spec.TargetReply, p.Silent, logger))
+	}
+	if u := r.Unpin; u != nil {
+		res = append(res, handlers.NewUnpin(u.Target == spec.TargetReply, u.All, logger))
+	}
+	if b := r.Ban; b != nil {
+		res = append(res, handlers.NewBan(b.Target == spec.TargetReply, b.Until, b.RevokeMessages, logger))
+	}
+	if u := r.Unban; u != nil {
+		res = append(res, handlers.NewUnban(u.Target == spec.TargetReply, logger))
+	}
+	if rs := r.Restrict; rs != nil {
+		p := rs.Permissions
+		res = append(res, handlers.NewRestrict(rs.Target == spec.TargetReply, rs.Until, telegram.ChatPermissions{
+			CanSendMessages:       p.SendMessages,
+			CanSendMediaMessages:  p.SendMedia,
+			CanSendPolls:          p.SendPolls,
+			CanSendOtherMessages:  p.SendOther,
+			CanAddWebPagePreviews: p.AddWebPagePreviews,
+			CanChangeInfo:         p.ChangeInfo,
+			CanInviteUsers:        p.InviteUsers,
+			CanPinMessages:        p.PinMessages,
+		}, logger))
+	}
+	if p := r.Promote; p != nil {
+		res = append(res, handlers.NewPromote(p.Target == spec.TargetReply, telegram.PromoteChatMemberConfig{
+			IsAnonymous:         p.Anonymous,
+			CanManageChat:       p.ManageChat,
+			CanChangeInfo:       p.ChangeInfo,
+			CanPostMessages:     p.PostMessages,
+			CanEditMessages:     p.EditMessages,
+			CanDeleteMessages:   p.DeleteMessages,
+			CanManageVideoChats: p.ManageVideoChats,
+			CanInviteUsers:      p.InviteUsers,
+			CanRestrictMembers:  p.RestrictMembers,
+			CanPinMessages:      p.PinMessages,
+			CanPromoteMembers:   p.PromoteMembers,
+		}, logger))
+	}
+	if r.SetChatTitle != "" {
+		res = append(res, handlers.NewSetChatTitle(r.SetChatTitle, logger))
+	}
+	return res
 }
 
-func newImageReply(s *spec.FileReply, assets types.Assets,
-	log zerolog.Logger,
-) types.Handler {
-	if s.Key != "" {
-		return handlers.NewReplyImageFile(s.Key, s.Name, assets, log)
+func mediaFileFromSpec(kind handlers.MediaKind, s *spec.FileReply) handlers.MediaFile {
+	return handlers.MediaFile{
+		Kind:      kind,
+		Key:       s.Key,
+		Name:      s.Name,
+		FileID:    s.FileID,
+		Caption:   s.Caption,
+		ParseMode: string(s.ParseMode),
 	}
-	return nil
 }
 
-func newDocumentReply(s *spec.FileReply, assets types.Assets,
-	log zerolog.Logger,
-) types.Handler {
-	if s.Key != "" {
-		return handlers.NewReplyDocument(s.Key, s.Name, assets, log)
+func newMediaReplies(r *spec.Reply, assets types.Assets, log zerolog.Logger) []types.Handler {
+	var res []types.Handler
+	files := []struct {
+		kind handlers.MediaKind
+		spec *spec.FileReply
+	}{
+		{handlers.MediaPhoto, r.Image},
+		{handlers.MediaDocument, r.Document},
+		{handlers.MediaAudio, r.Audio},
+		{handlers.MediaVoice, r.Voice},
+		{handlers.MediaVideo, r.Video},
+		{handlers.MediaAnimation, r.Animation},
+		{handlers.MediaSticker, r.Sticker},
 	}
-	return nil
+	for _, f := range files {
+		if f.spec != nil {
+			res = append(res, handlers.NewReplyMedia(mediaFileFromSpec(f.kind, f.spec), assets, log))
+		}
+	}
+	if len(r.MediaGroup) > 0 {
+		items := make([]handlers.MediaFile, len(r.MediaGroup))
+		for i, item := range r.MediaGroup {
+			items[i] = mediaFileFromSpec(handlers.MediaKind(item.Type), &item.FileReply)
+		}
+		res = append(res, handlers.NewReplyMediaGroup(items, assets, log))
+	}
+	if l := r.Location; l != nil {
+		res = append(res, handlers.NewReplyLocation(l.Latitude, l.Longitude, log))
+	}
+	if v := r.Venue; v != nil {
+		res = append(res, handlers.NewReplyVenue(v.Latitude, v.Longitude, v.Title, v.Address, log))
+	}
+	if c := r.Contact; c != nil {
+		res = append(res, handlers.NewReplyContact(c.PhoneNumber, c.FirstName, c.LastName, log))
+	}
+	if r.Dice != nil {
+		res = append(res, handlers.NewReplyDice(r.Dice.Emoji, log))
+	}
+	return res
 }
 
 func newInvoice(s *spec.Invoice, providers types.PaymentProviders, sp types.StateProvider, secrets types.Secrets,
@@ -195,6 +380,62 @@ func newPreCheckoutAnswer(s *spec.PreCheckoutAnswer, log zerolog.Logger) types.H
 	return handlers.NewPreCheckout(s.Ok, s.ErrorMessage, log)
 }
 
+func newJoinRequestAnswer(approve bool, log zerolog.Logger) types.Handler {
+	return handlers.NewJoinRequestAnswer(approve, log)
+}
+
+func newDelay(s *spec.Delay, jobs types.JobsProvider, log zerolog.Logger) types.Handler {
+	return handlers.NewDelay(handlers.DelayConfig{
+		Event:  s.Event,
+		After:  s.After,
+		At:     s.At,
+		Cancel: s.Cancel,
+	}, jobs, log)
+}
+
+func newPoll(s *spec.Poll, polls types.PollsProvider, log zerolog.Logger) types.Handler {
+	return handlers.NewSendPoll(handlers.PollConfig{
+		Name:            s.Name,
+		Question:        s.Question,
+		Options:         s.Options,
+		Foreach:         s.Foreach,
+		Option:          s.Option,
+		Quiz:            s.Quiz,
+		CorrectOption:   s.CorrectOption,
+		Explanation:     s.Explanation,
+		Anonymous:       s.Anonymous,
+		MultipleAnswers: s.MultipleAnswers,
+		OpenPeriod:      s.OpenPeriod,
+	}, polls, log)
+}
+
+func newInlineResults(s *spec.InlineResults, log zerolog.Logger) types.Handler {
+	cfg := handlers.InlineResultsConfig{
+		Foreach:   s.Foreach,
+		CacheTime: s.CacheTime,
+		Personal:  s.Personal,
+	}
+	for _, a := range s.Articles {
+		cfg.Articles = append(cfg.Articles, inlineArticleFromSpec(a))
+	}
+	if s.Article != nil {
+		cfg.Article = inlineArticleFromSpec(s.Article)
+	}
+	return handlers.NewInlineResults(cfg, log)
+}
+
+func inlineArticleFromSpec(s *spec.InlineArticle) *handlers.InlineArticle {
+	return &handlers.InlineArticle{
+		ID:          s.ID,
+		Title:       s.Title,
+		Description: s.Description,
+		ThumbURL:    s.ThumbURL,
+		Text:        s.Text,
+		ParseMode:   string(s.ParseMode),
+		Keyboard:    inlineKeyboardFromSpec(s.InlineKeyboard),
+	}
+}
+
 func Validator(s *spec.Validators, log zerolog.Logger) (types.Handler, error) {
 	checks := make([]handlers.Check, len(s.Checks))
 	for i, sc := range s.Checks {
@@ -207,3 +448,73 @@ func Validator(s *spec.Validators, log zerolog.Logger) (types.Handler, error) {
 	return handlers.NewValidator(log.With().Str("handler", "validator").Str("component", "validators").Logger(),
 		s.ErrorMessage, checks...), nil
 }
+
+// NewPaginate creates paginated list handler with default values for empty options.
+func NewPaginate(s *spec.Paginate, log zerolog.Logger) *handlers.Paginate {
+	cfg := handlers.PaginateConfig{
+		Name:      s.Name,
+		Foreach:   s.Foreach,
+		PageSize:  s.PageSize,
+		Header:    s.Header,
+		Item:      s.Item,
+		Footer:    s.Footer,
+		Empty:     s.Empty,
+		Prev:      s.Prev,
+		Next:      s.Next,
+		ParseMode: string(s.ParseMode),
+	}
+	if cfg.PageSize == 0 {
+		cfg.PageSize = 5
+	}
+	if cfg.Empty == "" {
+		cfg.Empty = "No items"
+	}
+	if cfg.Prev == "" {
+		cfg.Prev = "« Previous"
+	}
+	if cfg.Next == "" {
+		cfg.Next = "Next »"
+	}
+	return handlers.NewPaginate(cfg, log)
+}
+
+func newForward(s *spec.Forward, copy bool, adminChat string, log zerolog.Logger) types.Handler {
+	cfg := handlers.ForwardConfig{
+		To:        s.To,
+		Copy:      copy,
+		Caption:   s.Caption,
+		ParseMode: string(s.ParseMode),
+	}
+	if cfg.To == "" {
+		cfg.To = adminChat
+	}
+	return handlers.NewForwardMessage(cfg, log)
+}
+
+// SupportConfig creates support relay config with default values for empty options,
+// adminChat is a default operators chat.
+func SupportConfig(s *spec.Support, adminChat string) (handlers.SupportConfig, error) {
+	chat := s.Chat
+	if chat == "" {
+		chat = adminChat
+	}
+	id, err := strconv.ParseInt(chat, 10, 64)
+	if err != nil {
+		return handlers.SupportConfig{}, errors.Wrapf(err, "invalid support chat %q", chat)
+	}
+	cfg := handlers.SupportConfig{
+		Chat:         id,
+		Topic:        s.Topic,
+		CloseCommand: s.CloseCommand,
+		Header:       s.Header,
+		Closed:       s.Closed,
+	}
+	if cfg.CloseCommand == "" {
+		cfg.CloseCommand = "close"
+	}
+	if cfg.Header == "" {
+		cfg.Header = "Support request from ${user.first_name} (${user.id}), reply to messages to answer them, " +
+			"reply /" + cfg.CloseCommand + " to close the session"
+	}
+	return cfg, nil
+}
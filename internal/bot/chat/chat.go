@@ -0,0 +1,66 @@
+// Package chat resolves chat of telegram update.
+package chat
+
+import (
+	telegram "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// FromUpdate returns chat of the update or nil if update has no chat,
+// e.g. for inline queries or callbacks from inline messages.
+func FromUpdate(upd *telegram.Update) *telegram.Chat {
+	switch {
+	case upd.CallbackQuery != nil && upd.CallbackQuery.Message == nil:
+		return nil
+	case upd.MyChatMember != nil:
+		return &upd.MyChatMember.Chat
+	case upd.ChatMember != nil:
+		return &upd.ChatMember.Chat
+	case upd.ChatJoinRequest != nil:
+		return &upd.ChatJoinRequest.Chat
+	}
+	return upd.FromChat()
+}
+
+// Sender returns user who sent the update or nil if update has no sender.
+func Sender(upd *telegram.Update) *telegram.User {
+	switch {
+	case upd.MyChatMember != nil:
+		return &upd.MyChatMember.From
+	case upd.ChatMember != nil:
+		return &upd.ChatMember.From
+	case upd.ChatJoinRequest != nil:
+		return &upd.ChatJoinRequest.From
+	case upd.PollAnswer != nil:
+		return &upd.PollAnswer.User
+	}
+	return upd.SentFrom()
+}
+
+// ID returns chat ID of the update. It falls back to sender ID
+// for updates without chat (private chat ID is the same as user ID),
+// and returns -1 if update has no sender either.
+func ID(upd *telegram.Update) int64 {
+	if chat := FromUpdate(upd); chat != nil {
+		return chat.ID
+	}
+	if user := Sender(upd); user != nil {
+		return user.ID
+	}
+	return -1
+}
+
+// Message returns message of the update: new or edited message
+// or channel post, or nil if update has no message.
+func Message(upd *telegram.Update) *telegram.Message {
+	switch {
+	case upd.Message != nil:
+		return upd.Message
+	case upd.EditedMessage != nil:
+		return upd.EditedMessage
+	case upd.ChannelPost != nil:
+		return upd.ChannelPost
+	case upd.EditedChannelPost != nil:
+		return upd.EditedChannelPost
+	}
+	return nil
+}
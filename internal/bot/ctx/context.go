@@ -3,11 +3,20 @@ package ctx
 import (
 	"context"
 	"sync"
+	"time"
 
 	"github.com/g4s8/openbots/pkg/types"
+	"github.com/pkg/errors"
 )
 
-var _ types.Context = &Current{}
+var (
+	_ types.Context         = &Current{}
+	_ types.ExpiringContext = &Current{}
+)
+
+// ErrTTLNotSupported is returned on saving context value with TTL
+// if context provider doesn't support it.
+var ErrTTLNotSupported = errors.New("context provider doesn't support TTL")
 
 type pendingAction int
 
@@ -23,6 +32,7 @@ type Current struct {
 	current types.Context
 	act     pendingAction
 	newVal  string
+	ttl     time.Duration
 
 	mx sync.Mutex
 }
@@ -40,6 +50,13 @@ func (c *Current) Save(ctx context.Context) error {
 
 	switch c.act {
 	case actionSet:
+		if c.ttl > 0 {
+			ec, ok := c.current.(types.ExpiringContext)
+			if !ok {
+				return ErrTTLNotSupported
+			}
+			return ec.SetWithTTL(ctx, c.newVal, c.ttl)
+		}
 		return c.current.Set(ctx, c.newVal)
 	case actionReset:
 		return c.current.Reset(ctx)
@@ -52,6 +69,19 @@ func (c *Current) Set(ctx context.Context, val string) error {
 	defer c.mx.Unlock()
 
 	c.newVal = val
+	c.ttl = 0
+	c.act = actionSet
+
+	return nil
+}
+
+// SetWithTTL sets pending context value which expires after TTL.
+func (c *Current) SetWithTTL(ctx context.Context, val string, ttl time.Duration) error {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	c.newVal = val
+	c.ttl = ttl
 	c.act = actionSet
 
 	return nil
@@ -2,17 +2,31 @@ package ctx
 
 import (
 	"context"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/g4s8/openbots/pkg/types"
 	"github.com/pkg/errors"
 )
 
-var _ types.ContextProvider = &Provider{}
+var (
+	_ types.ContextProvider    = &Provider{}
+	_ types.ContextExpirer     = &Provider{}
+	_ types.ChatContextExpirer = &Provider{}
+)
 
 // Provider - context provider for pending context state.
 type Provider struct {
 	origin   types.ContextProvider
 	pendinng map[types.ChatID]*Current
+	// expired contexts cleared by ExpireChat, they are returned
+	// by the next Expire call.
+	expired []types.ExpiredContext
+	// expiring enables contexts expiration, it's disabled until some
+	// handler sets context with TTL or handles expired contexts.
+	expiring atomic.Bool
+	mx       sync.Mutex
 }
 
 func NewProvider(origin types.ContextProvider) *Provider {
@@ -22,11 +36,17 @@ func NewProvider(origin types.ContextProvider) *Provider {
 	}
 }
 
-// Closer - context closer. It saves pending context state on close.
+// Closer - context closer. It saves pending context state on close
+// and removes it from pending contexts.
 type Closer func(context.Context) error
 
-func newCloser(current *Current) Closer {
+func (p *Provider) newCloser(id types.ChatID, current *Current) Closer {
 	return func(ctx context.Context) error {
+		p.mx.Lock()
+		if p.pendinng[id] == current {
+			delete(p.pendinng, id)
+		}
+		p.mx.Unlock()
 		if err := current.Save(ctx); err != nil {
 			return errors.Wrap(err, "save context")
 		}
@@ -34,16 +54,75 @@ func newCloser(current *Current) Closer {
 	}
 }
 
+// Begin pending context of the chat, it's visible by UserContext
+// until the closer is called. Updates of the same chat should not be
+// handled concurrently, since the last Begin replaces pending context.
 func (p *Provider) Begin(id types.ChatID) Closer {
 	var pending Current
 	pending.Load(p.origin, id)
+	p.mx.Lock()
 	p.pendinng[id] = &pending
-	return newCloser(&pending)
+	p.mx.Unlock()
+	return p.newCloser(id, &pending)
 }
 
+// UserContext returns pending context of the chat, or origin context
+// if no update of the chat is being handled, e.g. for API calls.
 func (p *Provider) UserContext(id types.ChatID) types.Context {
+	p.mx.Lock()
+	defer p.mx.Unlock()
 	if ctx, ok := p.pendinng[id]; ok {
 		return ctx
 	}
-	return nil
+	return p.origin.UserContext(id)
+}
+
+// EnableExpiration of contexts by Expire and ExpireChat calls.
+func (p *Provider) EnableExpiration() {
+	p.expiring.Store(true)
+}
+
+// Expiring checks if contexts expiration is enabled.
+func (p *Provider) Expiring() bool {
+	return p.expiring.Load()
+}
+
+// Expire removes expired contexts of origin provider if it supports expiration,
+// it also returns contexts cleared by ExpireChat since the last call.
+// It does nothing if expiration is not enabled.
+func (p *Provider) Expire(ctx context.Context, now time.Time) ([]types.ExpiredContext, error) {
+	if !p.Expiring() {
+		return nil, nil
+	}
+	p.mx.Lock()
+	res := p.expired
+	p.expired = nil
+	p.mx.Unlock()
+	expirer, ok := p.origin.(types.ContextExpirer)
+	if !ok {
+		return res, nil
+	}
+	exp, err := expirer.Expire(ctx, now)
+	if err != nil {
+		return res, err
+	}
+	return append(res, exp...), nil
+}
+
+// ExpireChat removes expired context of the chat if origin provider supports it,
+// expired context is kept to be returned by the next Expire call.
+// It does nothing if expiration is not enabled.
+func (p *Provider) ExpireChat(ctx context.Context, id types.ChatID, now time.Time) (types.ExpiredContext, bool, error) {
+	expirer, ok := p.origin.(types.ChatContextExpirer)
+	if !ok || !p.Expiring() {
+		return types.ExpiredContext{}, false, nil
+	}
+	exp, ok, err := expirer.ExpireChat(ctx, id, now)
+	if err != nil || !ok {
+		return exp, ok, err
+	}
+	p.mx.Lock()
+	p.expired = append(p.expired, exp)
+	p.mx.Unlock()
+	return exp, true, nil
 }
@@ -0,0 +1,37 @@
+package ctx
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	botctx "github.com/g4s8/openbots/pkg/context"
+	"github.com/g4s8/openbots/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProviderExpiration(t *testing.T) {
+	ctx := context.Background()
+	origin := botctx.NewMemoryProvider()
+	p := NewProvider(origin)
+	uc := origin.UserContext(42).(types.ExpiringContext)
+	require.NoError(t, uc.SetWithTTL(ctx, "checkout", time.Millisecond))
+	later := time.Now().Add(time.Second)
+
+	_, ok, err := p.ExpireChat(ctx, 42, later)
+	require.NoError(t, err)
+	require.False(t, ok, "expiration is disabled")
+	exp, err := p.Expire(ctx, later)
+	require.NoError(t, err)
+	require.Empty(t, exp)
+
+	p.EnableExpiration()
+	require.True(t, p.Expiring())
+	expired, ok, err := p.ExpireChat(ctx, 42, later)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, types.ExpiredContext{ChatID: 42, Value: "checkout"}, expired)
+	exp, err = p.Expire(ctx, later)
+	require.NoError(t, err)
+	require.Equal(t, []types.ExpiredContext{expired}, exp, "lazily expired context is returned by sweeper")
+}
@@ -5,7 +5,9 @@ import (
 	"encoding/json"
 	"net/http"
 
+	"github.com/g4s8/openbots/internal/bot/chat"
 	"github.com/g4s8/openbots/internal/bot/interpolator"
+	"github.com/g4s8/openbots/internal/bot/match"
 	"github.com/g4s8/openbots/pkg/state"
 	"github.com/g4s8/openbots/pkg/types"
 	telegram "github.com/go-telegram-bot-api/telegram-bot-api/v5"
@@ -43,22 +45,19 @@ func (l *Loader) Load(ctx context.Context, c *types.DataContainer, upd *telegram
 	state := state.NewUserState()
 	defer state.Close()
 
-	chat := upd.FromChat()
-	var chatID int64
-	if chat == nil {
-		chatID = -1
-	} else {
-		chatID = chat.ID
-	}
-
-	if err := l.sp.Load(ctx, types.ChatID(chatID), state); err != nil {
+	if err := l.sp.Load(ctx, types.ChatID(chat.ID(upd)), state); err != nil {
 		return errors.Wrap(err, "load state")
 	}
 	secretMap, err := l.secrets.Get(ctx)
 	if err != nil {
 		return errors.Wrap(err, "get secrets")
 	}
-	ip := interpolator.New(state.Map(), secretMap, upd)
+	ip := interpolator.NewWithOps(
+		interpolator.WithState(state.Map()),
+		interpolator.WithSecrets(secretMap),
+		interpolator.WithUpdate(upd),
+		interpolator.WithMatch(match.FromCtx(ctx)),
+	)
 	req, err := http.NewRequestWithContext(ctx, l.cfg.Method, ip.Interpolate(l.cfg.URL), nil)
 	if err != nil {
 		return errors.Wrap(err, "create new request")
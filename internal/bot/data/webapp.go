@@ -0,0 +1,27 @@
+package data
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/g4s8/openbots/pkg/types"
+	telegram "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/pkg/errors"
+)
+
+var _ types.DataLoader = WebAppLoader{}
+
+// WebAppLoader loads JSON data sent by Mini App.
+type WebAppLoader struct{}
+
+func (WebAppLoader) Load(_ context.Context, c *types.DataContainer, upd *telegram.Update) error {
+	if upd.Message == nil || upd.Message.WebAppData == nil {
+		return errors.New("no web app data")
+	}
+	var data any
+	if err := json.Unmarshal([]byte(upd.Message.WebAppData.Data), &data); err != nil {
+		return errors.Wrap(err, "decode web app data")
+	}
+	c.Set(data)
+	return nil
+}
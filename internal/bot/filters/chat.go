@@ -0,0 +1,141 @@
+package filters
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/g4s8/openbots/internal/bot/chat"
+	"github.com/g4s8/openbots/pkg/types"
+	telegram "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/pkg/errors"
+)
+
+var (
+	_ types.EventFilter = (*ChatType)(nil)
+	_ types.EventFilter = (*Sender)(nil)
+)
+
+// ChatType filter matches updates by chat type: private, group, supergroup or channel.
+type ChatType struct {
+	types []string
+}
+
+func NewChatType(types []string) *ChatType {
+	return &ChatType{types: types}
+}
+
+func (f *ChatType) Check(ctx context.Context, upd *telegram.Update) (bool, error) {
+	c := chat.FromUpdate(upd)
+	if c == nil {
+		return false, nil
+	}
+	return slices.Contains(f.types, c.Type), nil
+}
+
+// adminCacheTTL is a time to keep chat administrator status of users.
+const adminCacheTTL = 5 * time.Minute
+
+// Sender filter matches updates sent by users from allowlist
+// or by chat administrators.
+type Sender struct {
+	ids       []int64
+	usernames []string
+	admins    *adminCache
+}
+
+// NewSender creates sender filter, if api is not nil
+// it also matches chat administrators.
+func NewSender(ids []int64, usernames []string, api *telegram.BotAPI) *Sender {
+	f := &Sender{ids: ids}
+	for _, u := range usernames {
+		f.usernames = append(f.usernames, strings.ToLower(strings.TrimPrefix(u, "@")))
+	}
+	if api != nil {
+		f.admins = newAdminCache(api, adminCacheTTL)
+	}
+	return f
+}
+
+func (f *Sender) Check(ctx context.Context, upd *telegram.Update) (bool, error) {
+	c := chat.FromUpdate(upd)
+	// anonymous group administrators send messages on behalf of the chat
+	if msg := chat.Message(upd); f.admins != nil && c != nil && msg != nil && msg.SenderChat != nil &&
+		msg.SenderChat.ID == c.ID {
+		return true, nil
+	}
+	user := chat.Sender(upd)
+	if user == nil {
+		return false, nil
+	}
+	if slices.Contains(f.ids, user.ID) {
+		return true, nil
+	}
+	if user.UserName != "" && slices.Contains(f.usernames, strings.ToLower(user.UserName)) {
+		return true, nil
+	}
+	if f.admins == nil || c == nil || c.IsPrivate() {
+		return false, nil
+	}
+	admin, err := f.admins.isAdmin(c.ID, user.ID)
+	if err != nil {
+		return false, errors.Wrap(err, "check chat administrator")
+	}
+	return admin, nil
+}
+
+type adminKey struct {
+	chatID, userID int64
+}
+
+type adminEntry struct {
+	admin   bool
+	expires time.Time
+}
+
+// adminCache keeps chat administrator status of users for TTL.
+type adminCache struct {
+	api     *telegram.BotAPI
+	ttl     time.Duration
+	entries map[adminKey]adminEntry
+	mux     sync.Mutex
+}
+
+func newAdminCache(api *telegram.BotAPI, ttl time.Duration) *adminCache {
+	return &adminCache{
+		api:     api,
+		ttl:     ttl,
+		entries: make(map[adminKey]adminEntry),
+	}
+}
+
+func (c *adminCache) isAdmin(chatID, userID int64) (bool, error) {
+	key := adminKey{chatID: chatID, userID: userID}
+	now := time.Now()
+	c.mux.Lock()
+	entry, ok := c.entries[key]
+	c.mux.Unlock()
+	if ok && now.Before(entry.expires) {
+		return entry.admin, nil
+	}
+
+	member, err := c.api.GetChatMember(telegram.GetChatMemberConfig{
+		ChatConfigWithUser: telegram.ChatConfigWithUser{ChatID: chatID, UserID: userID},
+	})
+	if err != nil {
+		return false, errors.Wrap(err, "get chat member")
+	}
+	admin := member.IsCreator() || member.IsAdministrator()
+
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	for k, e := range c.entries {
+		if now.After(e.expires) {
+			delete(c.entries, k)
+		}
+	}
+	c.entries[key] = adminEntry{admin: admin, expires: now.Add(c.ttl)}
+	return admin, nil
+}
@@ -0,0 +1,59 @@
+package filters
+
+import (
+	"context"
+	"slices"
+
+	"github.com/g4s8/openbots/pkg/types"
+	telegram "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+var (
+	_ types.EventFilter = (*ChatMember)(nil)
+	_ types.EventFilter = (*ChatJoinRequest)(nil)
+)
+
+// ChatMember filter matches chat member updates by new and old statuses.
+// If my is true, it matches bot's own member status updates,
+// otherwise it matches updates of other chat members.
+type ChatMember struct {
+	my        bool
+	status    []string
+	oldStatus []string
+}
+
+func NewChatMember(my bool, status, oldStatus []string) *ChatMember {
+	return &ChatMember{
+		my:        my,
+		status:    status,
+		oldStatus: oldStatus,
+	}
+}
+
+func (f *ChatMember) Check(ctx context.Context, upd *telegram.Update) (bool, error) {
+	m := upd.ChatMember
+	if f.my {
+		m = upd.MyChatMember
+	}
+	if m == nil {
+		return false, nil
+	}
+	if len(f.status) > 0 && !slices.Contains(f.status, m.NewChatMember.Status) {
+		return false, nil
+	}
+	if len(f.oldStatus) > 0 && !slices.Contains(f.oldStatus, m.OldChatMember.Status) {
+		return false, nil
+	}
+	return true, nil
+}
+
+// ChatJoinRequest filter matches chat join requests.
+type ChatJoinRequest struct{}
+
+func NewChatJoinRequest() *ChatJoinRequest {
+	return &ChatJoinRequest{}
+}
+
+func (f *ChatJoinRequest) Check(ctx context.Context, upd *telegram.Update) (bool, error) {
+	return upd.ChatJoinRequest != nil, nil
+}
@@ -0,0 +1,109 @@
+package filters
+
+import (
+	"cmp"
+	"context"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/g4s8/openbots/internal/bot/handlers"
+	"github.com/g4s8/openbots/pkg/types"
+	telegram "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Compare checks if value matches comparison.
+type Compare func(val string) bool
+
+func CompareEq(expected string) Compare {
+	return func(val string) bool { return val == expected }
+}
+
+func CompareNeq(expected string) Compare {
+	return func(val string) bool { return val != expected }
+}
+
+func CompareGt(expected string) Compare {
+	return func(val string) bool {
+		res, ok := compareValues(val, expected)
+		return ok && res > 0
+	}
+}
+
+func CompareLt(expected string) Compare {
+	return func(val string) bool {
+		res, ok := compareValues(val, expected)
+		return ok && res < 0
+	}
+}
+
+func CompareGte(expected string) Compare {
+	return func(val string) bool {
+		res, ok := compareValues(val, expected)
+		return ok && res >= 0
+	}
+}
+
+func CompareLte(expected string) Compare {
+	return func(val string) bool {
+		res, ok := compareValues(val, expected)
+		return ok && res <= 0
+	}
+}
+
+func CompareIn(expected []string) Compare {
+	return func(val string) bool { return slices.Contains(expected, val) }
+}
+
+func CompareMatches(re *regexp.Regexp) Compare {
+	return func(val string) bool { return re.MatchString(val) }
+}
+
+// CompareAll checks if value matches all comparisons.
+func CompareAll(cmps ...Compare) Compare {
+	return func(val string) bool {
+		for _, c := range cmps {
+			if !c(val) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// compareValues compares values as numbers if the right operand is a number,
+// or as strings otherwise. Values are not comparable if the left value is empty,
+// or it's not a number while the right operand is a number.
+func compareValues(left, right string) (int, bool) {
+	if left == "" {
+		return 0, false
+	}
+	r, rerr := strconv.ParseFloat(right, 64)
+	if rerr != nil {
+		return strings.Compare(left, right), true
+	}
+	l, lerr := strconv.ParseFloat(left, 64)
+	if lerr != nil {
+		return 0, false
+	}
+	return cmp.Compare(l, r), true
+}
+
+var _ types.EventFilter = (*Condition)(nil)
+
+// Condition filter interpolates value with update context
+// and checks it by comparison.
+type Condition struct {
+	value string
+	cmp   Compare
+}
+
+func NewCondition(value string, cmp Compare) *Condition {
+	return &Condition{value: value, cmp: cmp}
+}
+
+func (f *Condition) Check(ctx context.Context, _ *telegram.Update) (bool, error) {
+	val := handlers.UpdateContextFromCtx(ctx).Interpolator().Interpolate(f.value)
+	return f.cmp(val), nil
+}
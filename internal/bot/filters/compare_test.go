@@ -0,0 +1,79 @@
+package filters
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/g4s8/openbots/internal/bot/handlers"
+	"github.com/g4s8/openbots/pkg/secrets"
+	"github.com/g4s8/openbots/pkg/state"
+	"github.com/g4s8/openbots/pkg/types"
+	telegram "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompare(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		cmp  Compare
+		val  string
+		ok   bool
+	}{
+		{name: "lt number", cmp: CompareLt("100"), val: "99", ok: true},
+		{name: "lt number not less", cmp: CompareLt("100"), val: "100"},
+		{name: "lt numbers are not strings", cmp: CompareLt("100"), val: "20", ok: true},
+		{name: "lt missing value", cmp: CompareLt("100"), val: ""},
+		{name: "lt not a number", cmp: CompareLt("100"), val: "abc"},
+		{name: "gt missing value", cmp: CompareGt("0"), val: ""},
+		{name: "gt not a number", cmp: CompareGt("0"), val: "abc"},
+		{name: "gte float", cmp: CompareGte("1.5"), val: "1.50", ok: true},
+		{name: "lte negative", cmp: CompareLte("0"), val: "-1", ok: true},
+		{name: "gt strings", cmp: CompareGt("b"), val: "c", ok: true},
+		{name: "lt strings", cmp: CompareLt("b"), val: "c"},
+		{name: "lt strings missing value", cmp: CompareLt("b"), val: ""},
+		{name: "eq", cmp: CompareEq("a"), val: "a", ok: true},
+		{name: "neq", cmp: CompareNeq("a"), val: "b", ok: true},
+		{name: "in", cmp: CompareIn([]string{"a", "b"}), val: "b", ok: true},
+		{name: "not in", cmp: CompareIn([]string{"a", "b"}), val: "c"},
+		{name: "matches", cmp: CompareMatches(regexp.MustCompile(`^\d+$`)), val: "42", ok: true},
+		{name: "all", cmp: CompareAll(CompareGte("1"), CompareLte("10")), val: "5", ok: true},
+		{name: "all out of range", cmp: CompareAll(CompareGte("1"), CompareLte("10")), val: "11"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.ok, tc.cmp(tc.val))
+		})
+	}
+}
+
+func TestCondition(t *testing.T) {
+	sp := state.NewMemory(nil)
+	st := state.NewUserState()
+	st.Set("balance", "50")
+	require.NoError(t, sp.Update(context.Background(), 42, st))
+	st.Close()
+	upd := &telegram.Update{Message: &telegram.Message{
+		MessageID: 1, Text: "150",
+		Chat: &telegram.Chat{ID: 42, Type: "private"},
+		From: &telegram.User{ID: 42},
+	}}
+	ctx, err := handlers.NewUpdateContextProvider(secrets.Stub, sp).NewContext(context.Background(), upd)
+	require.NoError(t, err)
+
+	check := func(f types.EventFilter) bool {
+		ok, err := f.Check(ctx, upd)
+		require.NoError(t, err)
+		return ok
+	}
+	require.True(t, check(NewCondition("${state.balance}", CompareLt("100"))))
+	require.False(t, check(NewCondition("${message.text}", CompareLt("100"))))
+	require.False(t, check(NewCondition("${state.missing}", CompareLt("100"))), "missing value")
+	require.True(t, check(NewCondition("${state.missing}", CompareNeq("1"))))
+
+	low := NewCondition("${state.balance}", CompareLt("10"))
+	high := NewCondition("${message.text}", CompareGt("100"))
+	require.True(t, check(Any(low, high)))
+	require.False(t, check(Any(low, NewCondition("${state.missing}", CompareGt("0")))))
+	require.True(t, check(Not(low)))
+	require.False(t, check(Not(high)))
+}
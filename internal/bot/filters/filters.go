@@ -3,6 +3,7 @@ package filters
 import (
 	"context"
 
+	"github.com/g4s8/openbots/internal/bot/updates"
 	"github.com/g4s8/openbots/pkg/types"
 	telegram "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
@@ -31,6 +32,27 @@ func (nopFilter) Check(context.Context, *telegram.Update) (bool, error) { return
 // Fallback filter always returns true
 var Fallback = nopFilter{}
 
+type passFilter struct{}
+
+func (passFilter) Check(context.Context, *telegram.Update) (bool, error) { return true, nil }
+
+// Pass filter accepts any update, it's used by handlers which are not
+// triggered by telegram updates, e.g. scheduled handlers.
+var Pass = passFilter{}
+
+type userUpdateFilter struct{}
+
+func (userUpdateFilter) Check(ctx context.Context, upd *telegram.Update) (bool, error) {
+	if updates.ReactionFromCtx(ctx) != nil {
+		return false, nil
+	}
+	return upd.Message != nil || upd.CallbackQuery != nil, nil
+}
+
+// UserUpdate filter accepts user messages and callback queries, it's used
+// by handlers triggered only by conditions, e.g. context, state or `if` trigger.
+var UserUpdate = userUpdateFilter{}
+
 func Join(head types.EventFilter, tail ...types.EventFilter) FilterChain {
 	if head == nil {
 		head = nopFilter{}
@@ -0,0 +1,62 @@
+package filters
+
+import (
+	"context"
+	"regexp"
+
+	"github.com/g4s8/openbots/internal/bot/match"
+	"github.com/g4s8/openbots/pkg/types"
+	telegram "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+var (
+	_ types.EventFilter = (*InlineQuery)(nil)
+	_ types.EventFilter = (*ChosenInlineResult)(nil)
+)
+
+// InlineQuery filter matches inline queries by query text pattern.
+// It matches any inline query if pattern is nil.
+type InlineQuery struct {
+	pattern *regexp.Regexp
+}
+
+func NewInlineQuery(pattern *regexp.Regexp) *InlineQuery {
+	return &InlineQuery{pattern: pattern}
+}
+
+func (f *InlineQuery) Check(ctx context.Context, upd *telegram.Update) (bool, error) {
+	if upd.InlineQuery == nil {
+		return false, nil
+	}
+	return matchPattern(ctx, f.pattern, upd.InlineQuery.Query), nil
+}
+
+// ChosenInlineResult filter matches chosen inline results by result ID pattern.
+// It matches any chosen result if pattern is nil.
+type ChosenInlineResult struct {
+	pattern *regexp.Regexp
+}
+
+func NewChosenInlineResult(pattern *regexp.Regexp) *ChosenInlineResult {
+	return &ChosenInlineResult{pattern: pattern}
+}
+
+func (f *ChosenInlineResult) Check(ctx context.Context, upd *telegram.Update) (bool, error) {
+	if upd.ChosenInlineResult == nil {
+		return false, nil
+	}
+	return matchPattern(ctx, f.pattern, upd.ChosenInlineResult.ResultID), nil
+}
+
+// matchPattern checks text by pattern and saves named groups to match context.
+func matchPattern(ctx context.Context, re *regexp.Regexp, text string) bool {
+	if re == nil {
+		return true
+	}
+	sub := re.FindStringSubmatch(text)
+	if sub == nil {
+		return false
+	}
+	match.Set(ctx, match.Groups, match.Regexp(re.SubexpNames(), sub))
+	return true
+}
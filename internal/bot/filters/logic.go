@@ -0,0 +1,50 @@
+package filters
+
+import (
+	"context"
+
+	"github.com/g4s8/openbots/pkg/types"
+	telegram "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+var (
+	_ types.EventFilter = (AnyFilter)(nil)
+	_ types.EventFilter = (*NotFilter)(nil)
+)
+
+// AnyFilter accepts update if any of filters accepts it.
+type AnyFilter []types.EventFilter
+
+func Any(fs ...types.EventFilter) AnyFilter {
+	return AnyFilter(fs)
+}
+
+func (c AnyFilter) Check(ctx context.Context, upd *telegram.Update) (bool, error) {
+	for _, f := range c {
+		ok, err := f.Check(ctx, upd)
+		if err != nil {
+			return false, err
+		}
+		if ok {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// NotFilter inverts result of origin filter.
+type NotFilter struct {
+	origin types.EventFilter
+}
+
+func Not(origin types.EventFilter) *NotFilter {
+	return &NotFilter{origin: origin}
+}
+
+func (f *NotFilter) Check(ctx context.Context, upd *telegram.Update) (bool, error) {
+	ok, err := f.origin.Check(ctx, upd)
+	if err != nil {
+		return false, err
+	}
+	return !ok, nil
+}
@@ -0,0 +1,43 @@
+package filters
+
+import (
+	"context"
+	"slices"
+
+	"github.com/g4s8/openbots/internal/bot/updates"
+	"github.com/g4s8/openbots/pkg/types"
+	telegram "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+var _ types.EventFilter = (*PollAnswer)(nil)
+
+// PollAnswer filter matches answers of polls sent by the bot by poll name,
+// and optionally by correctness of quiz answer.
+type PollAnswer struct {
+	name    string
+	correct *bool
+}
+
+func NewPollAnswer(name string, correct *bool) *PollAnswer {
+	return &PollAnswer{name: name, correct: correct}
+}
+
+func (f *PollAnswer) Check(ctx context.Context, upd *telegram.Update) (bool, error) {
+	if upd.PollAnswer == nil {
+		return false, nil
+	}
+	poll := updates.PollFromCtx(ctx)
+	if poll == nil {
+		// poll was not sent by the bot or was sent before restart
+		// with in-memory polls storage
+		return f.name == "" && f.correct == nil, nil
+	}
+	if f.name != "" && poll.Name != f.name {
+		return false, nil
+	}
+	if f.correct != nil {
+		correct := poll.Quiz() && slices.Contains(upd.PollAnswer.OptionIDs, poll.CorrectOption)
+		return poll.Quiz() && correct == *f.correct, nil
+	}
+	return true, nil
+}
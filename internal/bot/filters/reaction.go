@@ -0,0 +1,35 @@
+package filters
+
+import (
+	"context"
+	"slices"
+
+	"github.com/g4s8/openbots/internal/bot/updates"
+	"github.com/g4s8/openbots/pkg/types"
+	telegram "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+var _ types.EventFilter = (*MessageReaction)(nil)
+
+// MessageReaction filter matches message reaction updates by new reaction emoji,
+// it matches any reaction update if emoji list is empty.
+type MessageReaction struct {
+	emoji []string
+}
+
+func NewMessageReaction(emoji []string) *MessageReaction {
+	return &MessageReaction{emoji: emoji}
+}
+
+func (f *MessageReaction) Check(ctx context.Context, _ *telegram.Update) (bool, error) {
+	r := updates.ReactionFromCtx(ctx)
+	if r == nil {
+		return false, nil
+	}
+	if len(f.emoji) == 0 {
+		return true, nil
+	}
+	return slices.ContainsFunc(r.New, func(rt updates.ReactionType) bool {
+		return slices.Contains(f.emoji, rt.String())
+	}), nil
+}
@@ -21,6 +21,7 @@ type StateFilter struct {
 	eq      string
 	neq     string
 	present *bool
+	cmp     Compare
 }
 
 func NewStateFilterWithPresent(sp types.StateProvider, logger zerolog.Logger, key string, present bool) *StateFilter {
@@ -50,6 +51,17 @@ func NewStateFilterNeq(sp types.StateProvider, logger zerolog.Logger, key, neq s
 	}
 }
 
+// NewStateFilterCompare creates state filter which checks
+// present value by comparison.
+func NewStateFilterCompare(sp types.StateProvider, logger zerolog.Logger, key string, cmp Compare) *StateFilter {
+	return &StateFilter{
+		sp:     sp,
+		logger: logger,
+		key:    key,
+		cmp:    cmp,
+	}
+}
+
 func (f *StateFilter) Check(ctx context.Context, upd *telegram.Update) (bool, error) {
 	uid := handlers.ChatID(upd)
 	state := state.NewUserState()
@@ -66,5 +78,8 @@ func (f *StateFilter) Check(ctx context.Context, upd *telegram.Update) (bool, er
 	if f.neq != "" {
 		return !ok || val != f.neq, nil
 	}
+	if f.cmp != nil {
+		return ok && f.cmp(val), nil
+	}
 	return false, errors.New("invalid state filter")
 }
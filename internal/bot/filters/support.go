@@ -0,0 +1,54 @@
+package filters
+
+import (
+	"context"
+
+	"github.com/g4s8/openbots/internal/bot/handlers"
+	"github.com/g4s8/openbots/internal/bot/updates"
+	"github.com/g4s8/openbots/pkg/state"
+	"github.com/g4s8/openbots/pkg/types"
+	telegram "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/pkg/errors"
+)
+
+var (
+	_ types.EventFilter = (*SupportUser)(nil)
+	_ types.EventFilter = (*SupportOperator)(nil)
+)
+
+// SupportUser filter matches user messages in private chats to relay them to operators.
+type SupportUser struct{}
+
+func (SupportUser) Check(ctx context.Context, upd *telegram.Update) (bool, error) {
+	if upd.Message == nil || updates.ReactionFromCtx(ctx) != nil {
+		return false, nil
+	}
+	return upd.Message.Chat.IsPrivate(), nil
+}
+
+// SupportOperator filter matches replies to relayed messages in operators chat,
+// other messages of operators chat are handled by other handlers.
+type SupportOperator struct {
+	chat int64
+	sp   types.StateProvider
+}
+
+func NewSupportOperator(chat int64, sp types.StateProvider) *SupportOperator {
+	return &SupportOperator{chat: chat, sp: sp}
+}
+
+func (f *SupportOperator) Check(ctx context.Context, upd *telegram.Update) (bool, error) {
+	if upd.Message == nil || updates.ReactionFromCtx(ctx) != nil {
+		return false, nil
+	}
+	if upd.Message.Chat.ID != f.chat || upd.Message.ReplyToMessage == nil {
+		return false, nil
+	}
+	st := state.NewUserState()
+	defer st.Close()
+	if err := f.sp.Load(ctx, types.ChatID(f.chat), st); err != nil {
+		return false, errors.Wrap(err, "load operators state")
+	}
+	_, ok := st.Get(handlers.SupportMessageKey(upd.Message.ReplyToMessage.MessageID))
+	return ok, nil
+}
@@ -0,0 +1,46 @@
+package filters
+
+import (
+	"context"
+	"testing"
+
+	"github.com/g4s8/openbots/internal/bot/handlers"
+	"github.com/g4s8/openbots/pkg/state"
+	telegram "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSupportOperator(t *testing.T) {
+	ctx := context.Background()
+	sp := state.NewMemory(nil)
+	st := state.NewUserState()
+	st.Set(handlers.SupportMessageKey(101), "42")
+	require.NoError(t, sp.Update(ctx, -100, st))
+	st.Close()
+	filter := NewSupportOperator(-100, sp)
+	reply := func(chatID int64, replyTo int) *telegram.Update {
+		return &telegram.Update{Message: &telegram.Message{
+			MessageID: 20, Text: "hello",
+			Chat:           &telegram.Chat{ID: chatID, Type: "supergroup"},
+			ReplyToMessage: &telegram.Message{MessageID: replyTo},
+		}}
+	}
+
+	ok, err := filter.Check(ctx, reply(-100, 101))
+	require.NoError(t, err)
+	require.True(t, ok, "reply to relayed message")
+
+	ok, err = filter.Check(ctx, reply(-100, 5))
+	require.NoError(t, err)
+	require.False(t, ok, "reply to other message of operators chat")
+
+	ok, err = filter.Check(ctx, reply(-200, 101))
+	require.NoError(t, err)
+	require.False(t, ok, "reply in other chat")
+
+	upd := reply(-100, 101)
+	upd.Message.ReplyToMessage = nil
+	ok, err = filter.Check(ctx, upd)
+	require.NoError(t, err)
+	require.False(t, ok, "not a reply")
+}
@@ -0,0 +1,27 @@
+package filters
+
+import (
+	"context"
+
+	"github.com/g4s8/openbots/pkg/types"
+	telegram "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+var _ types.EventFilter = (*WebAppData)(nil)
+
+// WebAppData filter matches messages with data sent by Mini App
+// by text of the button, it matches any button if text is empty.
+type WebAppData struct {
+	button string
+}
+
+func NewWebAppData(button string) *WebAppData {
+	return &WebAppData{button: button}
+}
+
+func (f *WebAppData) Check(_ context.Context, upd *telegram.Update) (bool, error) {
+	if upd.Message == nil || upd.Message.WebAppData == nil {
+		return false, nil
+	}
+	return f.button == "" || upd.Message.WebAppData.ButtonText == f.button, nil
+}
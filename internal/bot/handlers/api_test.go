@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/g4s8/openbots/pkg/secrets"
+	"github.com/g4s8/openbots/pkg/state"
+	"github.com/g4s8/openbots/pkg/types"
+	telegram "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/stretchr/testify/require"
+)
+
+// apiCall is a request to fake Telegram API.
+type apiCall struct {
+	method string
+	params url.Values
+}
+
+// testAPI is a fake Telegram API which records requests of handlers.
+// Sent messages get sequential IDs starting from 101.
+type testAPI struct {
+	mx     sync.Mutex
+	calls  []apiCall
+	lastID int
+}
+
+// newTestAPI starts fake Telegram API server and returns bot API client of it.
+func newTestAPI(t *testing.T) (*telegram.BotAPI, *testAPI) {
+	t.Helper()
+	fake := &testAPI{lastID: 100}
+	srv := httptest.NewServer(fake)
+	t.Cleanup(srv.Close)
+	api, err := telegram.NewBotAPIWithClient("123456:ABC-DEF", srv.URL+"/bot%s/%s", srv.Client())
+	require.NoError(t, err)
+	return api, fake
+}
+
+func (a *testAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	method := path.Base(r.URL.Path)
+	var result any = true
+	switch method {
+	case "getMe":
+		result = telegram.User{ID: 1, IsBot: true, UserName: "test_bot"}
+	case "sendMessage", "copyMessage", "editMessageText":
+		// edit of inline message returns true
+		if r.Form.Get("inline_message_id") != "" {
+			break
+		}
+		chatID, _ := strconv.ParseInt(r.Form.Get("chat_id"), 10, 64)
+		id, _ := strconv.Atoi(r.Form.Get("message_id"))
+		if method != "editMessageText" {
+			id = a.nextID()
+		}
+		result = telegram.Message{MessageID: id, Chat: &telegram.Chat{ID: chatID}}
+	}
+	if method != "getMe" {
+		a.mx.Lock()
+		a.calls = append(a.calls, apiCall{method: method, params: r.Form})
+		a.mx.Unlock()
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]any{"ok": true, "result": result})
+}
+
+func (a *testAPI) nextID() int {
+	a.mx.Lock()
+	defer a.mx.Unlock()
+	a.lastID++
+	return a.lastID
+}
+
+// requests returns recorded requests and clears them.
+func (a *testAPI) requests() []apiCall {
+	a.mx.Lock()
+	defer a.mx.Unlock()
+	res := a.calls
+	a.calls = nil
+	return res
+}
+
+// updateContext returns context of the update with chat state of provider.
+func updateContext(t *testing.T, upd *telegram.Update, sp types.StateProvider) context.Context {
+	t.Helper()
+	ctx, err := NewUpdateContextProvider(secrets.Stub, sp).NewContext(context.Background(), upd)
+	require.NoError(t, err)
+	return ctx
+}
+
+// chatState returns state values of the chat.
+func chatState(t *testing.T, sp types.StateProvider, chatID types.ChatID) map[string]string {
+	t.Helper()
+	st := state.NewUserState()
+	defer st.Close()
+	require.NoError(t, sp.Load(context.Background(), chatID, st))
+	return st.Map()
+}
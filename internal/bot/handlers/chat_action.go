@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"context"
+	"time"
+
+	"github.com/g4s8/openbots/internal/bot/updates"
+	"github.com/g4s8/openbots/pkg/types"
+	telegram "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+var _ types.Handler = (*ChatAction)(nil)
+
+// chatActionInterval is an interval to repeat chat action during the pause,
+// since telegram clients show the action for 5 seconds or less.
+const chatActionInterval = 4 * time.Second
+
+// ChatAction handler sends chat action, e.g. `typing`, and pauses
+// the next replies of the update.
+type ChatAction struct {
+	action string
+	pause  time.Duration
+	logger zerolog.Logger
+}
+
+// NewChatAction creates chat action handler, action or pause could be empty.
+func NewChatAction(action string, pause time.Duration, logger zerolog.Logger) *ChatAction {
+	return &ChatAction{
+		action: action,
+		pause:  pause,
+		logger: logger,
+	}
+}
+
+func (h *ChatAction) Handle(ctx context.Context, upd *telegram.Update, api *telegram.BotAPI) error {
+	if h.action != "" {
+		if err := h.send(ctx, upd, api); err != nil {
+			return err
+		}
+	}
+	if h.pause <= 0 {
+		return nil
+	}
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < h.pause {
+		return errors.Errorf("pause %s exceeds update timeout", h.pause)
+	}
+
+	h.logger.Debug().Dur("pause", h.pause).Msg("Pausing replies")
+	timer := time.NewTimer(h.pause)
+	defer timer.Stop()
+	ticker := time.NewTicker(chatActionInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return errors.Wrap(ctx.Err(), "pause")
+		case <-timer.C:
+			return nil
+		case <-ticker.C:
+			if h.action == "" {
+				continue
+			}
+			if err := h.send(ctx, upd, api); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// send raw request, since telegram library doesn't support forum topics.
+func (h *ChatAction) send(ctx context.Context, upd *telegram.Update, api *telegram.BotAPI) error {
+	params := make(telegram.Params)
+	params.AddNonZero64("chat_id", int64(ChatID(upd)))
+	params["action"] = h.action
+	params.AddNonZero("message_thread_id", updates.ThreadFromCtx(ctx))
+	if _, err := api.MakeRequest("sendChatAction", params); err != nil {
+		return errors.Wrap(err, "send chat action")
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/g4s8/openbots/internal/bot/updates"
+	telegram "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChatAction(t *testing.T) {
+	api, fake := newTestAPI(t)
+	upd := &telegram.Update{Message: &telegram.Message{
+		MessageID: 1,
+		Chat:      &telegram.Chat{ID: 42, Type: "supergroup"},
+	}}
+	ctx := updates.ContextWithThread(context.Background(), 5)
+
+	start := time.Now()
+	h := NewChatAction("typing", 50*time.Millisecond, zerolog.Nop())
+	require.NoError(t, h.Handle(ctx, upd, api))
+	require.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+	calls := fake.requests()
+	require.Len(t, calls, 1)
+	require.Equal(t, "sendChatAction", calls[0].method)
+	require.Equal(t, "42", calls[0].params.Get("chat_id"))
+	require.Equal(t, "typing", calls[0].params.Get("action"))
+	require.Equal(t, "5", calls[0].params.Get("message_thread_id"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	h = NewChatAction("", time.Minute, zerolog.Nop())
+	require.Error(t, h.Handle(ctx, upd, api), "pause exceeds update timeout")
+	require.Empty(t, fake.requests())
+}
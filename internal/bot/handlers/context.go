@@ -2,7 +2,9 @@ package handlers
 
 import (
 	"context"
+	"time"
 
+	botctx "github.com/g4s8/openbots/internal/bot/ctx"
 	"github.com/g4s8/openbots/pkg/api"
 	"github.com/g4s8/openbots/pkg/types"
 	telegram "github.com/go-telegram-bot-api/telegram-bot-api/v5"
@@ -13,6 +15,7 @@ import (
 type SetContextHandler struct {
 	cp    types.ContextProvider
 	value string
+	ttl   time.Duration
 	log   zerolog.Logger
 }
 
@@ -24,16 +27,33 @@ func NewContextSetter(cp types.ContextProvider, value string, log zerolog.Logger
 	}
 }
 
+// NewContextSetterWithTTL creates context setter for value which expires after TTL.
+func NewContextSetterWithTTL(cp types.ContextProvider, value string, ttl time.Duration, log zerolog.Logger) *SetContextHandler {
+	h := NewContextSetter(cp, value, log)
+	h.ttl = ttl
+	return h
+}
+
 func (h *SetContextHandler) Handle(ctx context.Context, upd *telegram.Update, api *telegram.BotAPI) error {
-	if err := h.cp.UserContext(ChatID(upd)).Set(ctx, h.value); err != nil {
-		return errors.Wrap(err, "set context")
-	}
-	return nil
+	return h.set(ctx, h.cp.UserContext(ChatID(upd)))
 }
 
 func (h *SetContextHandler) Call(ctx context.Context, req api.Request) error {
-	// TODO: refactor similar logic with Handle
-	if err := h.cp.UserContext(req.ChatID).Set(ctx, h.value); err != nil {
+	return h.set(ctx, h.cp.UserContext(req.ChatID))
+}
+
+func (h *SetContextHandler) set(ctx context.Context, c types.Context) error {
+	if h.ttl > 0 {
+		ec, ok := c.(types.ExpiringContext)
+		if !ok {
+			return botctx.ErrTTLNotSupported
+		}
+		if err := ec.SetWithTTL(ctx, h.value, h.ttl); err != nil {
+			return errors.Wrap(err, "set context with TTL")
+		}
+		return nil
+	}
+	if err := c.Set(ctx, h.value); err != nil {
 		return errors.Wrap(err, "set context")
 	}
 	return nil
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/g4s8/openbots/pkg/types"
+	telegram "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+var _ types.Handler = (*Delay)(nil)
+
+// DelayConfig configures delayed event.
+type DelayConfig struct {
+	Event  string
+	After  string
+	At     string
+	Cancel string
+}
+
+// Delay schedules or cancels delayed event of the chat.
+type Delay struct {
+	cfg    DelayConfig
+	jobs   types.JobsProvider
+	logger zerolog.Logger
+}
+
+func NewDelay(cfg DelayConfig, jobs types.JobsProvider, logger zerolog.Logger) *Delay {
+	return &Delay{
+		cfg:    cfg,
+		jobs:   jobs,
+		logger: logger.With().Str("handler", "delay").Logger(),
+	}
+}
+
+func (h *Delay) Handle(ctx context.Context, upd *telegram.Update, api *telegram.BotAPI) error {
+	uctx := UpdateContextFromCtx(ctx)
+	chatID := uctx.ChatID()
+	if h.cfg.Cancel != "" {
+		h.logger.Debug().Str("chat_id", chatID.String()).Str("event", h.cfg.Cancel).Msg("Cancel delayed event")
+		if err := h.jobs.Cancel(ctx, chatID, h.cfg.Cancel); err != nil {
+			return errors.Wrap(err, "cancel delayed event")
+		}
+	}
+	if h.cfg.Event == "" {
+		return nil
+	}
+
+	at, err := h.time(uctx.Interpolator())
+	if err != nil {
+		return err
+	}
+	h.logger.Debug().Str("chat_id", chatID.String()).Str("event", h.cfg.Event).Time("at", at).
+		Msg("Schedule delayed event")
+	if err := h.jobs.Add(ctx, types.Job{ChatID: chatID, Event: h.cfg.Event, At: at}); err != nil {
+		return errors.Wrap(err, "add delayed event")
+	}
+	return nil
+}
+
+// time of delayed event.
+func (h *Delay) time(ip Interpolator) (time.Time, error) {
+	if h.cfg.After != "" {
+		after := ip.Interpolate(h.cfg.After)
+		d, err := time.ParseDuration(after)
+		if err != nil {
+			return time.Time{}, errors.Wrapf(err, "parse delay duration %q", after)
+		}
+		return time.Now().Add(d), nil
+	}
+	at := ip.Interpolate(h.cfg.At)
+	if ts, err := strconv.ParseInt(at, 10, 64); err == nil {
+		return time.Unix(ts, 0), nil
+	}
+	t, err := time.Parse(time.RFC3339, at)
+	if err != nil {
+		return time.Time{}, errors.Wrapf(err, "parse delay time %q", at)
+	}
+	return t, nil
+}
@@ -2,7 +2,13 @@ package handlers
 
 import (
 	"context"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
 
+	"github.com/g4s8/openbots/internal/bot/match"
+	"github.com/g4s8/openbots/internal/bot/updates"
 	"github.com/g4s8/openbots/pkg/spec"
 	"github.com/g4s8/openbots/pkg/types"
 	telegram "github.com/go-telegram-bot-api/telegram-bot-api/v5"
@@ -17,37 +23,139 @@ var (
 
 // MessageFilter checks update by message criteria.
 type MessageFilter struct {
-	check messageCriteria
+	check  messageCriteria
+	source MessageSource
 }
 
+// MessageSource selects message of the update to check.
+type MessageSource func(*telegram.Update) *telegram.Message
+
+// Message sources of the update.
+var (
+	SourceMessage           MessageSource = func(upd *telegram.Update) *telegram.Message { return upd.Message }
+	SourceEditedMessage     MessageSource = func(upd *telegram.Update) *telegram.Message { return upd.EditedMessage }
+	SourceChannelPost       MessageSource = func(upd *telegram.Update) *telegram.Message { return upd.ChannelPost }
+	SourceEditedChannelPost MessageSource = func(upd *telegram.Update) *telegram.Message { return upd.EditedChannelPost }
+)
+
 func NewMessageFilterFromSpec(s *spec.MessageTrigger) (types.EventFilter, error) {
+	return NewMessageFilterWithSource(s, SourceMessage)
+}
+
+// NewMessageFilterWithSource creates message filter for the message
+// of the update selected by source, e.g. edited message or channel post.
+func NewMessageFilterWithSource(s *spec.MessageTrigger, source MessageSource) (types.EventFilter, error) {
 	if s.Command != "" {
+		var payload *regexp.Regexp
+		if s.Payload != nil {
+			payload = s.Payload.Regexp
+		}
 		return &MessageFilter{
-			check: messageHasCommand(s.Command),
+			check:  messageHasCommand(s.Command, s.Args, payload),
+			source: source,
 		}, nil
 	}
 	if len(s.Text) > 0 {
 		return &MessageFilter{
-			check: messageHasText(s.Text),
+			check:  messageHasText(s.Text),
+			source: source,
+		}, nil
+	}
+	if s.Pattern != nil {
+		return &MessageFilter{
+			check:  messageMatchesPattern(s.Pattern.Regexp),
+			source: source,
+		}, nil
+	}
+	if s.HasMedia() {
+		return &MessageFilter{
+			check:  messageHasMedia(s),
+			source: source,
 		}, nil
 	}
 	return nil, errors.New("unknown trigger")
 }
 
 func (h *MessageFilter) Check(ctx context.Context, update *telegram.Update) (bool, error) {
-	return update.Message != nil && h.check(update.Message), nil
+	if updates.ReactionFromCtx(ctx) != nil {
+		// reaction updates have synthetic message without content
+		return false, nil
+	}
+	msg := h.source(update)
+	return msg != nil && h.check(ctx, msg), nil
 }
 
-type messageCriteria func(*telegram.Message) bool
+type messageCriteria func(context.Context, *telegram.Message) bool
+
+// messageHasCommand checks message command and optionally its arguments
+// by payload pattern. Named arguments and payload groups are captured.
+func messageHasCommand(cmd string, args []string, payload *regexp.Regexp) messageCriteria {
+	return func(ctx context.Context, msg *telegram.Message) bool {
+		if msg.Command() != cmd {
+			return false
+		}
+		argsText := msg.CommandArguments()
+		if payload != nil {
+			submatches := payload.FindStringSubmatch(argsText)
+			if submatches == nil {
+				return false
+			}
+			match.Set(ctx, match.Groups, match.Regexp(payload.SubexpNames(), submatches))
+		}
+		if len(args) > 0 {
+			fields := strings.Fields(argsText)
+			named := make(map[string]string, len(args))
+			for i, name := range args {
+				if i < len(fields) {
+					named[name] = fields[i]
+				}
+			}
+			match.Set(ctx, match.Args, named)
+		}
+		return true
+	}
+}
+
+// messageMatchesPattern checks message text by regular expression
+// and captures its named groups.
+func messageMatchesPattern(re *regexp.Regexp) messageCriteria {
+	return func(ctx context.Context, msg *telegram.Message) bool {
+		submatches := re.FindStringSubmatch(msg.Text)
+		if submatches == nil {
+			return false
+		}
+		match.Set(ctx, match.Groups, match.Regexp(re.SubexpNames(), submatches))
+		return true
+	}
+}
 
-func messageHasCommand(cmd string) messageCriteria {
-	return func(msg *telegram.Message) bool {
-		return msg.Command() == cmd
+// messageHasMedia checks that message has any of media attachments
+// declared in trigger.
+func messageHasMedia(s *spec.MessageTrigger) messageCriteria {
+	return func(ctx context.Context, msg *telegram.Message) bool {
+		switch {
+		case s.Photo && len(msg.Photo) > 0:
+			return true
+		case s.Document != nil && msg.Document != nil:
+			return len(s.Document.Mime) == 0 || slices.Contains(s.Document.Mime, msg.Document.MimeType)
+		case s.Voice && msg.Voice != nil:
+			return true
+		case s.Video && msg.Video != nil:
+			return true
+		case s.Location && msg.Location != nil:
+			return true
+		case s.Contact && msg.Contact != nil:
+			return true
+		case s.Shared != nil:
+			shared := updates.SharedFromCtx(ctx)
+			return shared != nil && shared.RequestID == *s.Shared
+		}
+		return false
 	}
 }
 
 func messageHasText(texts []string) messageCriteria {
-	return func(msg *telegram.Message) bool {
+	return func(_ context.Context, msg *telegram.Message) bool {
 		for _, text := range texts {
 			if msg.Text == text {
 				return true
@@ -59,17 +167,82 @@ func messageHasText(texts []string) messageCriteria {
 
 // CallbackFilter check update callback data.
 type CallbackFilter struct {
-	callback string
+	check callbackCriteria
 }
 
 func (h *CallbackFilter) Check(ctx context.Context, update *telegram.Update) (bool, error) {
-	return update.CallbackQuery != nil && update.CallbackQuery.Data == h.callback, nil
+	if update.CallbackQuery == nil {
+		return false, nil
+	}
+	return h.check(ctx, update.CallbackQuery.Data), nil
 }
 
 func NewCallbackFilterFromSpec(s *spec.CallbackTrigger) (types.EventFilter, error) {
-	return &CallbackFilter{
-		callback: s.Data,
-	}, nil
+	if s.Pattern != nil {
+		return &CallbackFilter{check: callbackMatchesPattern(s.Pattern.Regexp)}, nil
+	}
+	if s.Prefix != "" {
+		return &CallbackFilter{check: callbackHasPrefix(s.Prefix)}, nil
+	}
+	if s.Template != "" {
+		re, err := callbackPlaceholdersPattern(s.Template)
+		if err != nil {
+			return nil, errors.Wrap(err, "compile callback data template")
+		}
+		return &CallbackFilter{check: callbackMatchesPattern(re)}, nil
+	}
+	return &CallbackFilter{check: callbackEquals(s.Data)}, nil
+}
+
+type callbackCriteria func(context.Context, string) bool
+
+func callbackEquals(expect string) callbackCriteria {
+	return func(_ context.Context, data string) bool {
+		return data == expect
+	}
+}
+
+// callbackHasPrefix matches data by prefix and captures
+// the rest of data as `suffix` parameter.
+func callbackHasPrefix(prefix string) callbackCriteria {
+	return func(ctx context.Context, data string) bool {
+		suffix, ok := strings.CutPrefix(data, prefix)
+		if ok {
+			match.Set(ctx, match.Callback, map[string]string{"suffix": suffix})
+		}
+		return ok
+	}
+}
+
+// callbackMatchesPattern matches data by regexp and captures named groups
+// as parameters.
+func callbackMatchesPattern(re *regexp.Regexp) callbackCriteria {
+	return func(ctx context.Context, data string) bool {
+		sub := re.FindStringSubmatch(data)
+		if sub == nil {
+			return false
+		}
+		match.Set(ctx, match.Callback, match.Regexp(re.SubexpNames(), sub))
+		return true
+	}
+}
+
+var callbackPlaceholder = regexp.MustCompile(`\{(\w+)\}`)
+
+// callbackPlaceholdersPattern compiles template with `{name}` placeholders
+// to anchored regexp with named groups, e.g. `item:{id}` -> `^item:(?P<id>.+?)$`.
+func callbackPlaceholdersPattern(data string) (*regexp.Regexp, error) {
+	var sb strings.Builder
+	sb.WriteString("^")
+	var pos int
+	for _, loc := range callbackPlaceholder.FindAllStringSubmatchIndex(data, -1) {
+		sb.WriteString(regexp.QuoteMeta(data[pos:loc[0]]))
+		sb.WriteString("(?P<" + data[loc[2]:loc[3]] + ">.+?)")
+		pos = loc[1]
+	}
+	sb.WriteString(regexp.QuoteMeta(data[pos:]))
+	sb.WriteString("$")
+	return regexp.Compile(sb.String())
 }
 
 type ContextFilter struct {
@@ -87,7 +260,15 @@ func NewContextFilter(base types.EventFilter, cp types.ContextProvider, val stri
 }
 
 func (h *ContextFilter) Check(ctx context.Context, update *telegram.Update) (bool, error) {
-	ctxCheck, err := h.cp.UserContext(ChatID(update)).Check(ctx, h.val)
+	chatID := ChatID(update)
+	// clear expired context lazily if expiration is enabled,
+	// expiration handlers are triggered by the sweeper
+	if expirer, ok := h.cp.(types.ChatContextExpirer); ok {
+		if _, _, err := expirer.ExpireChat(ctx, chatID, time.Now()); err != nil {
+			return false, errors.Wrap(err, "expire context")
+		}
+	}
+	ctxCheck, err := h.cp.UserContext(chatID).Check(ctx, h.val)
 	if err != nil {
 		return false, errors.Wrap(err, "check context")
 	}
@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/g4s8/openbots/internal/bot/match"
+	"github.com/g4s8/openbots/pkg/spec"
+	telegram "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCallbackPlaceholdersPattern(t *testing.T) {
+	re, err := callbackPlaceholdersPattern("item:{id}:{action}")
+	require.NoError(t, err)
+	require.Equal(t, `^item:(?P<id>.+?):(?P<action>.+?)$`, re.String())
+
+	re, err = callbackPlaceholdersPattern("a.b+{x}")
+	require.NoError(t, err)
+	require.True(t, re.MatchString("a.b+1"))
+	require.False(t, re.MatchString("axb+1"), "data outside of placeholders is quoted")
+}
+
+func TestCallbackFilter(t *testing.T) {
+	callback := func(data string) *telegram.Update {
+		return &telegram.Update{CallbackQuery: &telegram.CallbackQuery{ID: "1", Data: data}}
+	}
+	for _, tc := range []struct {
+		name    string
+		trigger spec.CallbackTrigger
+		data    string
+		ok      bool
+		params  map[string]string
+	}{
+		{name: "data", trigger: spec.CallbackTrigger{Data: "one"}, data: "one", ok: true},
+		{name: "data mismatch", trigger: spec.CallbackTrigger{Data: "one"}, data: "two"},
+		{
+			name: "data with braces", trigger: spec.CallbackTrigger{Data: "item:{id}"},
+			data: "item:{id}", ok: true,
+		},
+		{name: "data braces are not placeholders", trigger: spec.CallbackTrigger{Data: "item:{id}"}, data: "item:42"},
+		{
+			name: "template", trigger: spec.CallbackTrigger{Template: "item:{id}:{action}"},
+			data: "item:42:buy", ok: true, params: map[string]string{"id": "42", "action": "buy"},
+		},
+		{name: "template mismatch", trigger: spec.CallbackTrigger{Template: "item:{id}:{action}"}, data: "item:42"},
+		{name: "template empty value", trigger: spec.CallbackTrigger{Template: "item:{id}"}, data: "item:"},
+		{
+			name: "prefix", trigger: spec.CallbackTrigger{Prefix: "buy:"},
+			data: "buy:apple", ok: true, params: map[string]string{"suffix": "apple"},
+		},
+		{
+			name:    "pattern",
+			trigger: spec.CallbackTrigger{Pattern: &spec.Pattern{Regexp: regexp.MustCompile(`^page:(?P<num>\d+)$`)}},
+			data:    "page:2", ok: true, params: map[string]string{"num": "2"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			f, err := NewCallbackFilterFromSpec(&tc.trigger)
+			require.NoError(t, err)
+			ctx := match.NewContext(context.Background())
+			ok, err := f.Check(ctx, callback(tc.data))
+			require.NoError(t, err)
+			require.Equal(t, tc.ok, ok)
+			require.Equal(t, tc.params, match.FromCtx(ctx).Get(match.Callback))
+		})
+	}
+
+	f, err := NewCallbackFilterFromSpec(&spec.CallbackTrigger{Data: "one"})
+	require.NoError(t, err)
+	ok, err := f.Check(context.Background(), &telegram.Update{Message: &telegram.Message{Text: "one"}})
+	require.NoError(t, err)
+	require.False(t, ok, "not a callback query")
+}
@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/g4s8/openbots/internal/bot/chat"
+	"github.com/g4s8/openbots/pkg/types"
+	telegram "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+var _ types.Handler = (*ForwardMessage)(nil)
+
+// ErrNoMessage is returned when update has no message to forward.
+var ErrNoMessage = errors.New("update doesn't have message")
+
+// ForwardConfig configures forward or copy of the message.
+type ForwardConfig struct {
+	// To is a target chat ID or `@channel` username, it's interpolated.
+	To string
+	// Copy sends a copy of message without link to the original message.
+	Copy bool
+	// Caption overrides caption of copied message.
+	Caption   string
+	ParseMode string
+}
+
+// ForwardMessage forwards or copies the message of the update to another chat.
+type ForwardMessage struct {
+	cfg    ForwardConfig
+	logger zerolog.Logger
+}
+
+func NewForwardMessage(cfg ForwardConfig, logger zerolog.Logger) *ForwardMessage {
+	return &ForwardMessage{
+		cfg:    cfg,
+		logger: logger.With().Str("handler", "forward").Bool("copy", cfg.Copy).Logger(),
+	}
+}
+
+func (h *ForwardMessage) Handle(ctx context.Context, upd *telegram.Update, api *telegram.BotAPI) error {
+	msg := chat.Message(upd)
+	if msg == nil && upd.CallbackQuery != nil {
+		msg = upd.CallbackQuery.Message
+	}
+	if msg == nil {
+		return ErrNoMessage
+	}
+	ip := UpdateContextFromCtx(ctx).Interpolator()
+	to, err := parseChat(ip.Interpolate(h.cfg.To))
+	if err != nil {
+		return err
+	}
+	h.logger.Debug().Int64("from", msg.Chat.ID).Int("message_id", msg.MessageID).
+		Str("to", h.cfg.To).Msg("Forward message")
+
+	if !h.cfg.Copy {
+		cfg := telegram.NewForward(0, msg.Chat.ID, msg.MessageID)
+		cfg.BaseChat = to
+		// target chat is not the update chat, so reply and topic options are not applied
+		applySendOptions(ctx, nil, &cfg.BaseChat)
+		if _, err := api.Send(cfg); err != nil {
+			return errors.Wrap(err, "forward message")
+		}
+		return nil
+	}
+	cfg := telegram.NewCopyMessage(0, msg.Chat.ID, msg.MessageID)
+	cfg.BaseChat = to
+	applySendOptions(ctx, nil, &cfg.BaseChat)
+	if h.cfg.Caption != "" {
+		cfg.Caption = ip.Interpolate(h.cfg.Caption)
+		cfg.ParseMode = h.cfg.ParseMode
+	}
+	if _, err := api.Request(cfg); err != nil {
+		return errors.Wrap(err, "copy message")
+	}
+	return nil
+}
+
+// parseChat parses chat ID or `@channel` username.
+func parseChat(val string) (telegram.BaseChat, error) {
+	if strings.HasPrefix(val, "@") {
+		return telegram.BaseChat{ChannelUsername: val}, nil
+	}
+	id, err := strconv.ParseInt(val, 10, 64)
+	if err != nil {
+		return telegram.BaseChat{}, errors.Wrapf(err, "invalid target chat %q", val)
+	}
+	return telegram.BaseChat{ChatID: id}, nil
+}
@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/g4s8/openbots/pkg/types"
+	telegram "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+var _ types.Handler = (*InlineResults)(nil)
+
+// maxInlineResults is a maximum number of results allowed per inline query answer.
+const maxInlineResults = 50
+
+var ErrInlineQueryEmpty = errors.New("inline query is empty")
+
+// InlineArticle is an inline query result article template.
+type InlineArticle struct {
+	ID          string
+	Title       string
+	Description string
+	ThumbURL    string
+	Text        string
+	ParseMode   string
+	Keyboard    InlineKeyboard
+}
+
+func (a *InlineArticle) result(id string, ip Interpolator) telegram.InlineQueryResultArticle {
+	if a.ID != "" {
+		id = ip.Interpolate(a.ID)
+	}
+	res := telegram.InlineQueryResultArticle{
+		Type:  "article",
+		ID:    id,
+		Title: ip.Interpolate(a.Title),
+		InputMessageContent: telegram.InputTextMessageContent{
+			Text:      ip.Interpolate(a.Text),
+			ParseMode: a.ParseMode,
+		},
+		Description: ip.Interpolate(a.Description),
+		ThumbURL:    ip.Interpolate(a.ThumbURL),
+	}
+	if len(a.Keyboard) > 0 {
+		markup := a.Keyboard.telegramMarkup(ip)
+		res.ReplyMarkup = &markup
+	}
+	return res
+}
+
+// InlineResultsConfig configures inline query answer.
+type InlineResultsConfig struct {
+	// Articles is a static list of results.
+	Articles []*InlineArticle
+	// Foreach is a path to loaded data array, each item is rendered with Article.
+	Foreach string
+	Article *InlineArticle
+	// CacheTime in seconds.
+	CacheTime int
+	Personal  bool
+}
+
+// InlineResults answers inline query with articles.
+type InlineResults struct {
+	cfg    InlineResultsConfig
+	logger zerolog.Logger
+}
+
+func NewInlineResults(cfg InlineResultsConfig, logger zerolog.Logger) *InlineResults {
+	return &InlineResults{
+		cfg:    cfg,
+		logger: logger.With().Str("handler", "inline_results").Logger(),
+	}
+}
+
+func (h *InlineResults) Handle(ctx context.Context, upd *telegram.Update, api *telegram.BotAPI) error {
+	if upd.InlineQuery == nil {
+		return ErrInlineQueryEmpty
+	}
+
+	uctx := UpdateContextFromCtx(ctx)
+	var results []any
+	if h.cfg.Foreach != "" {
+		items, err := uctx.Items(h.cfg.Foreach)
+		if err != nil {
+			return errors.Wrap(err, "get inline results items")
+		}
+		for i, item := range items {
+			ip := uctx.ItemInterpolator(i, item)
+			results = append(results, h.cfg.Article.result(strconv.Itoa(i), ip))
+		}
+	} else {
+		ip := uctx.Interpolator()
+		for i, a := range h.cfg.Articles {
+			results = append(results, a.result(strconv.Itoa(i), ip))
+		}
+	}
+	if len(results) > maxInlineResults {
+		h.logger.Warn().Int("count", len(results)).Msg("Too many inline results, truncating")
+		results = results[:maxInlineResults]
+	}
+
+	h.logger.Debug().Str("query", upd.InlineQuery.ID).Int("count", len(results)).
+		Msg("Sending inline query answer")
+
+	answer := telegram.InlineConfig{
+		InlineQueryID: upd.InlineQuery.ID,
+		Results:       results,
+		CacheTime:     h.cfg.CacheTime,
+		IsPersonal:    h.cfg.Personal,
+	}
+	if _, err := api.Request(answer); err != nil {
+		return errors.Wrap(err, "send inline query answer")
+	}
+	return nil
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"strconv"
 
-	"github.com/g4s8/openbots/internal/bot/interpolator"
 	"github.com/g4s8/openbots/pkg/state"
 	"github.com/g4s8/openbots/pkg/types"
 	telegram "github.com/go-telegram-bot-api/telegram-bot-api/v5"
@@ -61,7 +60,7 @@ func (h *SendInvoice) Handle(ctx context.Context, upd *telegram.Update, api *tel
 	if err != nil {
 		return errors.Wrap(err, "get secrets")
 	}
-	interpolator := interpolator.New(state.Map(), secretMap, upd)
+	interpolator := UpdateContextFromCtx(ctx).interpolatorWith(state.Map(), secretMap)
 	prices := make([]telegram.LabeledPrice, len(h.config.Prices))
 	for i, p := range h.config.Prices {
 		amount := interpolator.Interpolate(p.Amount)
@@ -87,6 +86,11 @@ func (h *SendInvoice) Handle(ctx context.Context, upd *telegram.Update, api *tel
 		title, description, h.config.Payload, token, "", h.config.Currency, prices)
 	msg.MaxTipAmount = 10000
 	msg.SuggestedTipAmounts = []int{100, 500, 1000, 5000}
+	applySendOptions(ctx, upd, &msg.BaseChat)
+	api, err = threadAPI(ctx, api)
+	if err != nil {
+		return err
+	}
 	if _, err := api.Request(msg); err != nil {
 		return errors.WithMessage(err, "send invoice")
 	}
@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/g4s8/openbots/pkg/types"
+	telegram "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+var _ types.Handler = (*JoinRequestAnswer)(nil)
+
+var ErrJoinRequestEmpty = errors.New("chat join request is empty")
+
+// JoinRequestAnswer approves or declines chat join request.
+type JoinRequestAnswer struct {
+	approve bool
+	logger  zerolog.Logger
+}
+
+func NewJoinRequestAnswer(approve bool, logger zerolog.Logger) *JoinRequestAnswer {
+	return &JoinRequestAnswer{
+		approve: approve,
+		logger:  logger.With().Str("handler", "join_request_answer").Logger(),
+	}
+}
+
+func (h *JoinRequestAnswer) Handle(ctx context.Context, upd *telegram.Update, api *telegram.BotAPI) error {
+	req := upd.ChatJoinRequest
+	if req == nil {
+		return ErrJoinRequestEmpty
+	}
+
+	h.logger.Debug().Int64("chat_id", req.Chat.ID).Int64("user_id", req.From.ID).
+		Bool("approve", h.approve).Msg("Answering chat join request")
+
+	chat := telegram.ChatConfig{ChatID: req.Chat.ID}
+	var answer telegram.Chattable
+	if h.approve {
+		answer = telegram.ApproveChatJoinRequestConfig{ChatConfig: chat, UserID: req.From.ID}
+	} else {
+		answer = telegram.DeclineChatJoinRequest{ChatConfig: chat, UserID: req.From.ID}
+	}
+	if _, err := api.Request(answer); err != nil {
+		return errors.Wrap(err, "answer chat join request")
+	}
+	return nil
+}
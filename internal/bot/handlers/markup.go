@@ -2,12 +2,14 @@ package handlers
 
 import (
 	telegram "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/pkg/errors"
 )
 
 type InlineButton struct {
 	Text     string
 	URL      string
 	Callback string
+	WebApp   string
 }
 
 type InlineKeyboard [][]InlineButton
@@ -21,10 +23,139 @@ func (k InlineKeyboard) telegramMarkup(ip Interpolator) telegram.InlineKeyboardM
 			if btn.URL != "" {
 				setStr(&buttonRow[j].URL, ip.Interpolate(btn.URL))
 			} else if btn.Callback != "" {
-				setStr(&buttonRow[j].CallbackData, btn.Callback)
+				setStr(&buttonRow[j].CallbackData, ip.Interpolate(btn.Callback))
+			} else if btn.WebApp != "" {
+				buttonRow[j].WebApp = &telegram.WebAppInfo{URL: ip.Interpolate(btn.WebApp)}
 			}
 		}
 		buttons[i] = buttonRow
 	}
 	return telegram.NewInlineKeyboardMarkup(buttons...)
 }
+
+// InlineKeyboardForeach is an inline keyboard with a button
+// for each item of data array or state list.
+type InlineKeyboardForeach struct {
+	// Foreach is a path to items, see UpdateContext.Items.
+	Foreach string
+	Button  InlineButton
+	// Columns is a number of buttons in a row.
+	Columns int
+}
+
+// telegramMarkup renders button for each item, it returns nil if there are no items,
+// since telegram doesn't accept empty inline keyboard.
+func (k InlineKeyboardForeach) telegramMarkup(uctx *UpdateContext) (*telegram.InlineKeyboardMarkup, error) {
+	items, err := uctx.Items(k.Foreach)
+	if err != nil {
+		return nil, errors.Wrap(err, "get inline keyboard items")
+	}
+	if len(items) == 0 {
+		return nil, nil
+	}
+	columns := k.Columns
+	if columns <= 0 {
+		columns = 1
+	}
+	buttons := make([]telegram.InlineKeyboardButton, len(items))
+	for i, item := range items {
+		btn := InlineKeyboard{{k.Button}}.telegramMarkup(uctx.ItemInterpolator(i, item))
+		buttons[i] = btn.InlineKeyboard[0][0]
+	}
+	var markup telegram.InlineKeyboardMarkup
+	for i := 0; i < len(buttons); i += columns {
+		markup.InlineKeyboard = append(markup.InlineKeyboard, buttons[i:min(i+columns, len(buttons))])
+	}
+	return &markup, nil
+}
+
+// KeyboardButton is a button of chat keyboard.
+type KeyboardButton struct {
+	Text            string
+	WebApp          string
+	RequestContact  bool
+	RequestLocation bool
+	// RequestPoll is a type of requested poll: `quiz`, `regular` or `any`.
+	RequestPoll  string
+	RequestUsers *KeyboardRequestUsers
+	RequestChat  *KeyboardRequestChat
+}
+
+// KeyboardRequestUsers is a criteria of users requested by keyboard button.
+type KeyboardRequestUsers struct {
+	RequestID     int   `json:"request_id"`
+	UserIsBot     *bool `json:"user_is_bot,omitempty"`
+	UserIsPremium *bool `json:"user_is_premium,omitempty"`
+	MaxQuantity   int   `json:"max_quantity,omitempty"`
+}
+
+// KeyboardRequestChat is a criteria of chat requested by keyboard button.
+type KeyboardRequestChat struct {
+	RequestID       int   `json:"request_id"`
+	ChatIsChannel   bool  `json:"chat_is_channel"`
+	ChatIsForum     *bool `json:"chat_is_forum,omitempty"`
+	ChatHasUsername *bool `json:"chat_has_username,omitempty"`
+	ChatIsCreated   bool  `json:"chat_is_created,omitempty"`
+	BotIsMember     bool  `json:"bot_is_member,omitempty"`
+}
+
+// KeyboardOptions are display options of chat keyboard.
+type KeyboardOptions struct {
+	OneTime bool
+	Resize  bool
+	// Placeholder of input field, it's interpolated.
+	Placeholder string
+	Selective   bool
+}
+
+type Keyboard [][]KeyboardButton
+
+// keyboardButton extends telegram keyboard button with users and chat requests,
+// since telegram library doesn't support them.
+type keyboardButton struct {
+	telegram.KeyboardButton
+	RequestUsers *KeyboardRequestUsers `json:"request_users,omitempty"`
+	RequestChat  *KeyboardRequestChat  `json:"request_chat,omitempty"`
+}
+
+// replyKeyboardMarkup is a telegram reply keyboard markup with extended buttons.
+type replyKeyboardMarkup struct {
+	Keyboard              [][]keyboardButton `json:"keyboard"`
+	ResizeKeyboard        bool               `json:"resize_keyboard,omitempty"`
+	OneTimeKeyboard       bool               `json:"one_time_keyboard,omitempty"`
+	InputFieldPlaceholder string             `json:"input_field_placeholder,omitempty"`
+	Selective             bool               `json:"selective,omitempty"`
+}
+
+func (k Keyboard) telegramMarkup(ip Interpolator, opts KeyboardOptions) replyKeyboardMarkup {
+	buttons := make([][]keyboardButton, len(k))
+	for i, row := range k {
+		buttonRow := make([]keyboardButton, len(row))
+		for j, btn := range row {
+			b := &buttonRow[j]
+			b.Text = btn.Text
+			if btn.WebApp != "" {
+				b.WebApp = &telegram.WebAppInfo{URL: ip.Interpolate(btn.WebApp)}
+			}
+			b.RequestContact = btn.RequestContact
+			b.RequestLocation = btn.RequestLocation
+			switch btn.RequestPoll {
+			case "":
+			case "any":
+				b.RequestPoll = &telegram.KeyboardButtonPollType{}
+			default:
+				b.RequestPoll = &telegram.KeyboardButtonPollType{Type: btn.RequestPoll}
+			}
+			b.RequestUsers = btn.RequestUsers
+			b.RequestChat = btn.RequestChat
+		}
+		buttons[i] = buttonRow
+	}
+	return replyKeyboardMarkup{
+		Keyboard:              buttons,
+		ResizeKeyboard:        opts.Resize,
+		OneTimeKeyboard:       opts.OneTime,
+		InputFieldPlaceholder: ip.Interpolate(opts.Placeholder),
+		Selective:             opts.Selective,
+	}
+}
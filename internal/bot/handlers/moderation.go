@@ -0,0 +1,221 @@
+package handlers
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/g4s8/openbots/internal/bot/chat"
+	"github.com/g4s8/openbots/pkg/types"
+	telegram "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+var _ types.Handler = (*Moderate)(nil)
+
+// ErrNoTarget is returned when the update has no message or user
+// to moderate, e.g. the message doesn't reply to another message.
+var ErrNoTarget = errors.New("no moderation target")
+
+// ModerationTarget is a message and its sender affected by moderation action.
+type ModerationTarget struct {
+	Message *telegram.Message
+	User    *telegram.User
+}
+
+// ModerateBuilder builds moderation request for the chat and target
+// using interpolator of update context.
+type ModerateBuilder func(chatID int64, target ModerationTarget, ip Interpolator) (telegram.Chattable, error)
+
+// Moderate sends moderation request built by ModerateBuilder, e.g. pin or ban.
+type Moderate struct {
+	name   string
+	reply  bool
+	build  ModerateBuilder
+	logger zerolog.Logger
+}
+
+// NewModerate creates new Moderate handler, the target is the update message and
+// its sender or replied message and its sender if reply is true.
+func NewModerate(name string, reply bool, build ModerateBuilder, logger zerolog.Logger) *Moderate {
+	return &Moderate{
+		name:   name,
+		reply:  reply,
+		build:  build,
+		logger: logger.With().Str("handler", name).Logger(),
+	}
+}
+
+func (h *Moderate) Handle(ctx context.Context, upd *telegram.Update, api *telegram.BotAPI) error {
+	uctx := UpdateContextFromCtx(ctx)
+	target := moderationTarget(upd, h.reply)
+	req, err := h.build(uctx.ChatID().Int64(), target, uctx.Interpolator())
+	if err != nil {
+		return errors.Wrapf(err, "build %s", h.name)
+	}
+	h.logger.Debug().Str("chat_id", uctx.ChatID().String()).Msg("Moderate chat")
+	if _, err := api.Request(req); err != nil {
+		return errors.Wrap(err, h.name)
+	}
+	return nil
+}
+
+// moderationTarget of the update. Callback message is a target of callback updates,
+// but the user is a sender of callback query.
+func moderationTarget(upd *telegram.Update, reply bool) ModerationTarget {
+	msg := chat.Message(upd)
+	if msg == nil && upd.CallbackQuery != nil {
+		msg = upd.CallbackQuery.Message
+	}
+	if !reply {
+		return ModerationTarget{Message: msg, User: chat.Sender(upd)}
+	}
+	if msg == nil || msg.ReplyToMessage == nil {
+		return ModerationTarget{}
+	}
+	return ModerationTarget{Message: msg.ReplyToMessage, User: msg.ReplyToMessage.From}
+}
+
+func (t ModerationTarget) message() (*telegram.Message, error) {
+	if t.Message == nil {
+		return nil, ErrNoTarget
+	}
+	return t.Message, nil
+}
+
+func (t ModerationTarget) member(chatID int64) (telegram.ChatMemberConfig, error) {
+	if t.User == nil {
+		return telegram.ChatMemberConfig{}, ErrNoTarget
+	}
+	return telegram.ChatMemberConfig{ChatID: chatID, UserID: t.User.ID}, nil
+}
+
+// NewDelete creates handler to delete the message.
+func NewDelete(reply bool, logger zerolog.Logger) *Moderate {
+	return NewModerate("delete", reply, func(chatID int64, t ModerationTarget, _ Interpolator) (telegram.Chattable, error) {
+		msg, err := t.message()
+		if err != nil {
+			return nil, err
+		}
+		return telegram.NewDeleteMessage(chatID, msg.MessageID), nil
+	}, logger)
+}
+
+// NewPin creates handler to pin the message.
+func NewPin(reply, silent bool, logger zerolog.Logger) *Moderate {
+	return NewModerate("pin", reply, func(chatID int64, t ModerationTarget, _ Interpolator) (telegram.Chattable, error) {
+		msg, err := t.message()
+		if err != nil {
+			return nil, err
+		}
+		return telegram.PinChatMessageConfig{
+			ChatID:              chatID,
+			MessageID:           msg.MessageID,
+			DisableNotification: silent,
+		}, nil
+	}, logger)
+}
+
+// NewUnpin creates handler to unpin the message or all messages of the chat.
+func NewUnpin(reply, all bool, logger zerolog.Logger) *Moderate {
+	return NewModerate("unpin", reply, func(chatID int64, t ModerationTarget, _ Interpolator) (telegram.Chattable, error) {
+		if all {
+			return telegram.UnpinAllChatMessagesConfig{ChatID: chatID}, nil
+		}
+		msg, err := t.message()
+		if err != nil {
+			return nil, err
+		}
+		return telegram.UnpinChatMessageConfig{ChatID: chatID, MessageID: msg.MessageID}, nil
+	}, logger)
+}
+
+// NewBan creates handler to ban the user, until time is interpolated.
+func NewBan(reply bool, until string, revokeMessages bool, logger zerolog.Logger) *Moderate {
+	return NewModerate("ban", reply, func(chatID int64, t ModerationTarget, ip Interpolator) (telegram.Chattable, error) {
+		member, err := t.member(chatID)
+		if err != nil {
+			return nil, err
+		}
+		untilDate, err := parseUntil(ip.Interpolate(until))
+		if err != nil {
+			return nil, err
+		}
+		return telegram.BanChatMemberConfig{
+			ChatMemberConfig: member,
+			UntilDate:        untilDate,
+			RevokeMessages:   revokeMessages,
+		}, nil
+	}, logger)
+}
+
+// NewUnban creates handler to unban the user.
+func NewUnban(reply bool, logger zerolog.Logger) *Moderate {
+	return NewModerate("unban", reply, func(chatID int64, t ModerationTarget, _ Interpolator) (telegram.Chattable, error) {
+		member, err := t.member(chatID)
+		if err != nil {
+			return nil, err
+		}
+		return telegram.UnbanChatMemberConfig{ChatMemberConfig: member, OnlyIfBanned: true}, nil
+	}, logger)
+}
+
+// NewRestrict creates handler to restrict permissions of the user, until time is interpolated.
+func NewRestrict(reply bool, until string, permissions telegram.ChatPermissions, logger zerolog.Logger) *Moderate {
+	return NewModerate("restrict", reply, func(chatID int64, t ModerationTarget, ip Interpolator) (telegram.Chattable, error) {
+		member, err := t.member(chatID)
+		if err != nil {
+			return nil, err
+		}
+		untilDate, err := parseUntil(ip.Interpolate(until))
+		if err != nil {
+			return nil, err
+		}
+		return telegram.RestrictChatMemberConfig{
+			ChatMemberConfig: member,
+			UntilDate:        untilDate,
+			Permissions:      &permissions,
+		}, nil
+	}, logger)
+}
+
+// NewPromote creates handler to promote the user with rights of config,
+// chat and user of config are set by the handler.
+func NewPromote(reply bool, rights telegram.PromoteChatMemberConfig, logger zerolog.Logger) *Moderate {
+	return NewModerate("promote", reply, func(chatID int64, t ModerationTarget, _ Interpolator) (telegram.Chattable, error) {
+		member, err := t.member(chatID)
+		if err != nil {
+			return nil, err
+		}
+		cfg := rights
+		cfg.ChatMemberConfig = member
+		return cfg, nil
+	}, logger)
+}
+
+// NewSetChatTitle creates handler to change title of the chat, title is interpolated.
+func NewSetChatTitle(title string, logger zerolog.Logger) *Moderate {
+	return NewModerate("set_chat_title", false, func(chatID int64, _ ModerationTarget, ip Interpolator) (telegram.Chattable, error) {
+		return telegram.SetChatTitleConfig{ChatID: chatID, Title: ip.Interpolate(title)}, nil
+	}, logger)
+}
+
+// parseUntil parses duration from now, unix timestamp or RFC3339 time
+// to unix timestamp, it returns zero for empty string.
+func parseUntil(until string) (int64, error) {
+	if until == "" {
+		return 0, nil
+	}
+	if d, err := time.ParseDuration(until); err == nil {
+		return time.Now().Add(d).Unix(), nil
+	}
+	if ts, err := strconv.ParseInt(until, 10, 64); err == nil {
+		return ts, nil
+	}
+	t, err := time.Parse(time.RFC3339, until)
+	if err != nil {
+		return 0, errors.Wrapf(err, "parse until time %q", until)
+	}
+	return t.Unix(), nil
+}
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/g4s8/openbots/pkg/state"
+	telegram "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestModerate(t *testing.T) {
+	api, fake := newTestAPI(t)
+	sp := state.NewMemory(nil)
+	group := &telegram.Chat{ID: -100, Type: "supergroup"}
+	upd := &telegram.Update{Message: &telegram.Message{
+		MessageID: 10, Chat: group, From: &telegram.User{ID: 7}, Text: "/ban",
+		ReplyToMessage: &telegram.Message{MessageID: 5, Chat: group, From: &telegram.User{ID: 9}},
+	}}
+	ctx := updateContext(t, upd, sp)
+
+	require.NoError(t, NewDelete(false, zerolog.Nop()).Handle(ctx, upd, api))
+	calls := fake.requests()
+	require.Len(t, calls, 1)
+	require.Equal(t, "deleteMessage", calls[0].method)
+	require.Equal(t, "-100", calls[0].params.Get("chat_id"))
+	require.Equal(t, "10", calls[0].params.Get("message_id"))
+
+	require.NoError(t, NewPin(true, true, zerolog.Nop()).Handle(ctx, upd, api))
+	calls = fake.requests()
+	require.Len(t, calls, 1)
+	require.Equal(t, "pinChatMessage", calls[0].method)
+	require.Equal(t, "5", calls[0].params.Get("message_id"))
+	require.Equal(t, "true", calls[0].params.Get("disable_notification"))
+
+	require.NoError(t, NewBan(true, "1h", true, zerolog.Nop()).Handle(ctx, upd, api))
+	calls = fake.requests()
+	require.Len(t, calls, 1)
+	require.Equal(t, "banChatMember", calls[0].method)
+	require.Equal(t, "9", calls[0].params.Get("user_id"))
+	require.Equal(t, "true", calls[0].params.Get("revoke_messages"))
+	until, err := strconv.ParseInt(calls[0].params.Get("until_date"), 10, 64)
+	require.NoError(t, err)
+	require.InDelta(t, time.Now().Add(time.Hour).Unix(), until, 5)
+
+	noReply := &telegram.Update{Message: &telegram.Message{
+		MessageID: 11, Chat: group, From: &telegram.User{ID: 7}, Text: "/ban",
+	}}
+	err = NewBan(true, "", false, zerolog.Nop()).Handle(updateContext(t, noReply, sp), noReply, api)
+	require.ErrorIs(t, err, ErrNoTarget)
+	require.Empty(t, fake.requests())
+
+	callback := &telegram.Update{CallbackQuery: &telegram.CallbackQuery{
+		ID: "1", From: &telegram.User{ID: 7}, Data: "delete",
+		Message: &telegram.Message{MessageID: 12, Chat: group},
+	}}
+	require.NoError(t, NewDelete(false, zerolog.Nop()).Handle(updateContext(t, callback, sp), callback, api))
+	calls = fake.requests()
+	require.Len(t, calls, 1)
+	require.Equal(t, "12", calls[0].params.Get("message_id"), "callback message is deleted")
+}
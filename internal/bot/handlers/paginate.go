@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/g4s8/openbots/pkg/types"
+	telegram "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+var _ types.Handler = (*Paginate)(nil)
+
+// PaginateCallbackPrefix returns callback data prefix of
+// previous and next buttons of paginated list.
+func PaginateCallbackPrefix(name string) string {
+	return "paginate:" + name + ":"
+}
+
+// PaginateConfig configures paginated list.
+type PaginateConfig struct {
+	Name string
+	// Foreach is a path to items, see UpdateContext.Items.
+	Foreach  string
+	PageSize int
+	Header   string
+	Item     string
+	Footer   string
+	// Empty is a text of message without items.
+	Empty     string
+	Prev      string
+	Next      string
+	ParseMode string
+}
+
+// Paginate sends the first page of list with previous and next buttons,
+// and edits the message in place on button callbacks. The requested page
+// is kept in callback data of buttons, so each message has its own page.
+type Paginate struct {
+	cfg    PaginateConfig
+	logger zerolog.Logger
+}
+
+func NewPaginate(cfg PaginateConfig, logger zerolog.Logger) *Paginate {
+	return &Paginate{
+		cfg:    cfg,
+		logger: logger.With().Str("handler", "paginate").Str("name", cfg.Name).Logger(),
+	}
+}
+
+func (h *Paginate) Handle(ctx context.Context, upd *telegram.Update, api *telegram.BotAPI) error {
+	uctx := UpdateContextFromCtx(ctx)
+	items, err := uctx.Items(h.cfg.Foreach)
+	if err != nil {
+		return errors.Wrap(err, "get paginate items")
+	}
+	pages := (len(items) + h.cfg.PageSize - 1) / h.cfg.PageSize
+	page, edit := h.callbackPage(upd)
+	page = max(min(page, pages-1), 0)
+
+	text := h.render(uctx, items, page, pages)
+	keyboard := h.keyboard(page, pages)
+	chatID := uctx.ChatID()
+	h.logger.Debug().Str("chat_id", chatID.String()).Int("page", page).Int("pages", pages).
+		Bool("edit", edit).Msg("Paginate")
+
+	var msg telegram.Chattable
+	if edit {
+		cfg := telegram.EditMessageTextConfig{Text: text}
+		if cbm := upd.CallbackQuery.Message; cbm != nil {
+			cfg.ChatID = chatID.Int64()
+			cfg.MessageID = cbm.MessageID
+		} else {
+			cfg.InlineMessageID = upd.CallbackQuery.InlineMessageID
+		}
+		cfg.ParseMode = h.cfg.ParseMode
+		cfg.DisableWebPagePreview = sendOptionsFromCtx(ctx).DisableLinkPreview
+		cfg.ReplyMarkup = keyboard
+		msg = cfg
+	} else {
+		cfg := telegram.NewMessage(chatID.Int64(), text)
+		cfg.ParseMode = h.cfg.ParseMode
+		applySendOptions(ctx, upd, &cfg.BaseChat)
+		var err error
+		if api, err = threadAPI(ctx, api); err != nil {
+			return err
+		}
+		cfg.DisableWebPagePreview = sendOptionsFromCtx(ctx).DisableLinkPreview
+		if keyboard != nil {
+			cfg.ReplyMarkup = *keyboard
+		}
+		msg = cfg
+	}
+	if edit {
+		// edit of inline message returns true instead of message
+		if _, err := api.Request(msg); err != nil {
+			return errors.Wrap(err, "edit page")
+		}
+		if _, err := api.Request(telegram.NewCallback(upd.CallbackQuery.ID, "")); err != nil {
+			return errors.Wrap(err, "answer page callback")
+		}
+		return nil
+	}
+	if _, err := api.Send(msg); err != nil {
+		return errors.Wrap(err, "send page")
+	}
+	return nil
+}
+
+// callbackPage returns requested page of previous or next button callback
+// of chat or inline message, it returns false if update is not a paginate callback.
+func (h *Paginate) callbackPage(upd *telegram.Update) (int, bool) {
+	if upd.CallbackQuery == nil || upd.CallbackQuery.Message == nil && upd.CallbackQuery.InlineMessageID == "" {
+		return 0, false
+	}
+	val, ok := strings.CutPrefix(upd.CallbackQuery.Data, PaginateCallbackPrefix(h.cfg.Name))
+	if !ok {
+		return 0, false
+	}
+	page, err := strconv.Atoi(val)
+	if err != nil {
+		return 0, false
+	}
+	return page, true
+}
+
+func (h *Paginate) render(uctx *UpdateContext, items []any, page, pages int) string {
+	ip := uctx.PageInterpolator(page+1, pages)
+	if len(items) == 0 {
+		return ip.Interpolate(h.cfg.Empty)
+	}
+	var parts []string
+	if h.cfg.Header != "" {
+		parts = append(parts, ip.Interpolate(h.cfg.Header))
+	}
+	start := page * h.cfg.PageSize
+	end := min(start+h.cfg.PageSize, len(items))
+	for i := start; i < end; i++ {
+		parts = append(parts, uctx.PageItemInterpolator(page+1, pages, i, items[i]).Interpolate(h.cfg.Item))
+	}
+	if h.cfg.Footer != "" {
+		parts = append(parts, ip.Interpolate(h.cfg.Footer))
+	}
+	return strings.Join(parts, "\n")
+}
+
+// keyboard returns previous and next buttons, it returns nil for a single page.
+func (h *Paginate) keyboard(page, pages int) *telegram.InlineKeyboardMarkup {
+	var row []telegram.InlineKeyboardButton
+	prefix := PaginateCallbackPrefix(h.cfg.Name)
+	if page > 0 {
+		row = append(row, telegram.NewInlineKeyboardButtonData(h.cfg.Prev, fmt.Sprintf("%s%d", prefix, page-1)))
+	}
+	if page < pages-1 {
+		row = append(row, telegram.NewInlineKeyboardButtonData(h.cfg.Next, fmt.Sprintf("%s%d", prefix, page+1)))
+	}
+	if len(row) == 0 {
+		return nil
+	}
+	markup := telegram.NewInlineKeyboardMarkup(row)
+	return &markup
+}
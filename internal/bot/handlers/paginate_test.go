@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/g4s8/openbots/pkg/state"
+	telegram "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPaginate(t *testing.T) {
+	api, fake := newTestAPI(t)
+	sp := state.NewMemory(nil)
+	st := state.NewUserState()
+	st.Set("items", "a,b,c,d,e")
+	require.NoError(t, sp.Update(context.Background(), 42, st))
+	st.Close()
+	h := NewPaginate(PaginateConfig{
+		Name:     "list",
+		Foreach:  "state.items",
+		PageSize: 2,
+		Header:   "Page ${page} of ${pages}",
+		Item:     "${index}: ${item}",
+		Prev:     "<",
+		Next:     ">",
+	}, zerolog.Nop())
+	buttons := func(call apiCall) []string {
+		var markup telegram.InlineKeyboardMarkup
+		require.NoError(t, json.Unmarshal([]byte(call.params.Get("reply_markup")), &markup))
+		var res []string
+		for _, btn := range markup.InlineKeyboard[0] {
+			res = append(res, *btn.CallbackData)
+		}
+		return res
+	}
+	chat := &telegram.Chat{ID: 42, Type: "private"}
+	user := &telegram.User{ID: 42}
+
+	upd := &telegram.Update{Message: &telegram.Message{MessageID: 1, Chat: chat, From: user, Text: "/list"}}
+	require.NoError(t, h.Handle(updateContext(t, upd, sp), upd, api))
+	calls := fake.requests()
+	require.Len(t, calls, 1)
+	require.Equal(t, "sendMessage", calls[0].method)
+	require.Equal(t, "Page 1 of 3\n0: a\n1: b", calls[0].params.Get("text"))
+	require.Equal(t, []string{"paginate:list:1"}, buttons(calls[0]))
+
+	upd = &telegram.Update{CallbackQuery: &telegram.CallbackQuery{
+		ID: "1", From: user, Data: "paginate:list:2",
+		Message: &telegram.Message{MessageID: 101, Chat: chat},
+	}}
+	require.NoError(t, h.Handle(updateContext(t, upd, sp), upd, api))
+	calls = fake.requests()
+	require.Len(t, calls, 2)
+	require.Equal(t, "editMessageText", calls[0].method)
+	require.Equal(t, "42", calls[0].params.Get("chat_id"))
+	require.Equal(t, "101", calls[0].params.Get("message_id"))
+	require.Equal(t, "Page 3 of 3\n4: e", calls[0].params.Get("text"))
+	require.Equal(t, []string{"paginate:list:1"}, buttons(calls[0]))
+	require.Equal(t, "answerCallbackQuery", calls[1].method)
+
+	upd = &telegram.Update{CallbackQuery: &telegram.CallbackQuery{
+		ID: "2", From: user, Data: "paginate:list:1", InlineMessageID: "inline-1",
+	}}
+	require.NoError(t, h.Handle(updateContext(t, upd, sp), upd, api))
+	calls = fake.requests()
+	require.Len(t, calls, 2)
+	require.Equal(t, "editMessageText", calls[0].method, "inline message is edited in place")
+	require.Equal(t, "inline-1", calls[0].params.Get("inline_message_id"))
+	require.Empty(t, calls[0].params.Get("chat_id"))
+	require.Equal(t, "Page 2 of 3\n2: c\n3: d", calls[0].params.Get("text"))
+	require.Equal(t, []string{"paginate:list:0", "paginate:list:2"}, buttons(calls[0]))
+}
@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/g4s8/openbots/pkg/types"
+	telegram "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+var _ types.Handler = (*SendPoll)(nil)
+
+// PollConfig configures poll or quiz.
+type PollConfig struct {
+	Name     string
+	Question string
+	// Options is a static list of options.
+	Options []string
+	// Foreach is a path to loaded data array, each item is rendered with Option.
+	Foreach string
+	Option  string
+	Quiz    bool
+	// CorrectOption is an index of correct quiz option.
+	CorrectOption   string
+	Explanation     string
+	Anonymous       bool
+	MultipleAnswers bool
+	OpenPeriod      int
+}
+
+// SendPoll sends poll to the chat and keeps it in polls provider,
+// so poll answers could be mapped to the chat.
+type SendPoll struct {
+	cfg    PollConfig
+	polls  types.PollsProvider
+	logger zerolog.Logger
+}
+
+func NewSendPoll(cfg PollConfig, polls types.PollsProvider, logger zerolog.Logger) *SendPoll {
+	return &SendPoll{
+		cfg:    cfg,
+		polls:  polls,
+		logger: logger.With().Str("handler", "poll").Logger(),
+	}
+}
+
+func (h *SendPoll) Handle(ctx context.Context, upd *telegram.Update, api *telegram.BotAPI) error {
+	uctx := UpdateContextFromCtx(ctx)
+	ip := uctx.Interpolator()
+	options, err := h.options(uctx)
+	if err != nil {
+		return err
+	}
+
+	chatID := uctx.ChatID()
+	cfg := telegram.NewPoll(chatID.Int64(), ip.Interpolate(h.cfg.Question), options...)
+	cfg.IsAnonymous = h.cfg.Anonymous
+	cfg.AllowsMultipleAnswers = h.cfg.MultipleAnswers
+	cfg.OpenPeriod = h.cfg.OpenPeriod
+	applySendOptions(ctx, upd, &cfg.BaseChat)
+	api, err = threadAPI(ctx, api)
+	if err != nil {
+		return err
+	}
+	correct := -1
+	if h.cfg.Quiz {
+		val := ip.Interpolate(h.cfg.CorrectOption)
+		correct, err = strconv.Atoi(val)
+		if err != nil || correct < 0 || correct >= len(options) {
+			return errors.Errorf("invalid quiz correct option %q", val)
+		}
+		cfg.Type = "quiz"
+		cfg.CorrectOptionID = int64(correct)
+		cfg.Explanation = ip.Interpolate(h.cfg.Explanation)
+	}
+
+	h.logger.Debug().Str("chat_id", chatID.String()).Str("poll", h.cfg.Name).Msg("Sending poll")
+	msg, err := api.Send(cfg)
+	if err != nil {
+		return errors.Wrap(err, "send poll")
+	}
+	if msg.Poll == nil || h.cfg.Anonymous {
+		return nil
+	}
+	poll := types.Poll{
+		ID:            msg.Poll.ID,
+		ChatID:        chatID,
+		MessageID:     msg.MessageID,
+		Name:          h.cfg.Name,
+		Options:       options,
+		CorrectOption: correct,
+	}
+	if err := h.polls.Add(ctx, poll); err != nil {
+		return errors.Wrap(err, "add poll")
+	}
+	return nil
+}
+
+func (h *SendPoll) options(uctx *UpdateContext) ([]string, error) {
+	if h.cfg.Foreach == "" {
+		ip := uctx.Interpolator()
+		res := make([]string, len(h.cfg.Options))
+		for i, opt := range h.cfg.Options {
+			res[i] = ip.Interpolate(opt)
+		}
+		return res, nil
+	}
+	items, err := uctx.Items(h.cfg.Foreach)
+	if err != nil {
+		return nil, errors.Wrap(err, "get poll options items")
+	}
+	res := make([]string, len(items))
+	for i, item := range items {
+		res[i] = uctx.ItemInterpolator(i, item).Interpolate(h.cfg.Option)
+	}
+	return res, nil
+}
@@ -3,7 +3,6 @@ package handlers
 import (
 	"context"
 
-	"github.com/g4s8/openbots/internal/bot/interpolator"
 	"github.com/g4s8/openbots/pkg/state"
 	"github.com/g4s8/openbots/pkg/types"
 	telegram "github.com/go-telegram-bot-api/telegram-bot-api/v5"
@@ -48,7 +47,7 @@ func (h *CallbackReply) Handle(ctx context.Context, upd *telegram.Update,
 	if err != nil {
 		return errors.Wrap(err, "get secrets")
 	}
-	interpolator := interpolator.New(state.Map(), secretMap, upd)
+	interpolator := UpdateContextFromCtx(ctx).interpolatorWith(state.Map(), secretMap)
 	text := interpolator.Interpolate(h.text)
 
 	resp := telegram.NewCallback(upd.CallbackQuery.ID, text)
@@ -0,0 +1,207 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/g4s8/openbots/pkg/types"
+	telegram "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+var (
+	_ types.Handler = (*ReplyMedia)(nil)
+	_ types.Handler = (*ReplyMediaGroup)(nil)
+)
+
+// MediaKind is a kind of media file.
+type MediaKind string
+
+const (
+	MediaPhoto     MediaKind = "photo"
+	MediaDocument  MediaKind = "document"
+	MediaAudio     MediaKind = "audio"
+	MediaVoice     MediaKind = "voice"
+	MediaVideo     MediaKind = "video"
+	MediaAnimation MediaKind = "animation"
+	MediaSticker   MediaKind = "sticker"
+)
+
+// MediaFile is a file from assets by key or remote file by ID or URL.
+type MediaFile struct {
+	Kind MediaKind
+	// Key and Name of assets file.
+	Key  string
+	Name string
+	// FileID or URL of remote file, it's interpolated.
+	FileID    string
+	Caption   string
+	ParseMode string
+}
+
+// load file data, returned closer should be closed after sending the file.
+func (f *MediaFile) load(ctx context.Context, assets types.Assets, ip Interpolator) (telegram.RequestFileData, io.Closer, error) {
+	if f.Key == "" {
+		id := ip.Interpolate(f.FileID)
+		if strings.HasPrefix(id, "https://") || strings.HasPrefix(id, "http://") {
+			return telegram.FileURL(id), io.NopCloser(nil), nil
+		}
+		return telegram.FileID(id), io.NopCloser(nil), nil
+	}
+	asset, err := assets.LoadAsset(ctx, f.Key)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "load asset")
+	}
+	return telegram.FileReader{Name: f.Name, Reader: bufio.NewReader(asset)}, asset, nil
+}
+
+// ReplyMedia sends media file to chat.
+type ReplyMedia struct {
+	file   MediaFile
+	assets types.Assets
+	logger zerolog.Logger
+}
+
+// NewReplyMedia creates new ReplyMedia handler for the file.
+func NewReplyMedia(file MediaFile, assets types.Assets, logger zerolog.Logger) *ReplyMedia {
+	return &ReplyMedia{
+		file:   file,
+		assets: assets,
+		logger: logger.With().Str("handler", "reply_media").Str("kind", string(file.Kind)).Logger(),
+	}
+}
+
+func (h *ReplyMedia) Handle(ctx context.Context, upd *telegram.Update, api *telegram.BotAPI) error {
+	uctx := UpdateContextFromCtx(ctx)
+	ip := uctx.Interpolator()
+	data, closer, err := h.file.load(ctx, h.assets, ip)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := closer.Close(); err != nil {
+			h.logger.Error().Err(err).Msg("Failed to close asset file")
+		}
+	}()
+
+	chatID := uctx.ChatID().Int64()
+	caption := ip.Interpolate(h.file.Caption)
+	base := telegram.BaseChat{ChatID: chatID}
+	applySendOptions(ctx, upd, &base)
+	api, err = threadAPI(ctx, api)
+	if err != nil {
+		return err
+	}
+	var msg telegram.Chattable
+	switch h.file.Kind {
+	case MediaPhoto:
+		cfg := telegram.NewPhoto(chatID, data)
+		cfg.BaseChat = base
+		cfg.Caption, cfg.ParseMode = caption, h.file.ParseMode
+		msg = cfg
+	case MediaDocument:
+		cfg := telegram.NewDocument(chatID, data)
+		cfg.BaseChat = base
+		cfg.Caption, cfg.ParseMode = caption, h.file.ParseMode
+		msg = cfg
+	case MediaAudio:
+		cfg := telegram.NewAudio(chatID, data)
+		cfg.BaseChat = base
+		cfg.Caption, cfg.ParseMode = caption, h.file.ParseMode
+		msg = cfg
+	case MediaVoice:
+		cfg := telegram.NewVoice(chatID, data)
+		cfg.BaseChat = base
+		cfg.Caption, cfg.ParseMode = caption, h.file.ParseMode
+		msg = cfg
+	case MediaVideo:
+		cfg := telegram.NewVideo(chatID, data)
+		cfg.BaseChat = base
+		cfg.Caption, cfg.ParseMode = caption, h.file.ParseMode
+		msg = cfg
+	case MediaAnimation:
+		cfg := telegram.NewAnimation(chatID, data)
+		cfg.BaseChat = base
+		cfg.Caption, cfg.ParseMode = caption, h.file.ParseMode
+		msg = cfg
+	case MediaSticker:
+		cfg := telegram.NewSticker(chatID, data)
+		cfg.BaseChat = base
+		msg = cfg
+	default:
+		return fmt.Errorf("unsupported media kind %q", h.file.Kind)
+	}
+	if _, err := api.Send(msg); err != nil {
+		return errors.Wrapf(err, "send %s", h.file.Kind)
+	}
+	return nil
+}
+
+// ReplyMediaGroup sends an album of photos and videos.
+type ReplyMediaGroup struct {
+	files  []MediaFile
+	assets types.Assets
+	logger zerolog.Logger
+}
+
+// NewReplyMediaGroup creates new ReplyMediaGroup handler, files
+// should be photos or videos.
+func NewReplyMediaGroup(files []MediaFile, assets types.Assets, logger zerolog.Logger) *ReplyMediaGroup {
+	return &ReplyMediaGroup{
+		files:  files,
+		assets: assets,
+		logger: logger.With().Str("handler", "reply_media_group").Logger(),
+	}
+}
+
+func (h *ReplyMediaGroup) Handle(ctx context.Context, upd *telegram.Update, api *telegram.BotAPI) error {
+	uctx := UpdateContextFromCtx(ctx)
+	ip := uctx.Interpolator()
+	media := make([]any, len(h.files))
+	for i := range h.files {
+		f := &h.files[i]
+		data, closer, err := f.load(ctx, h.assets, ip)
+		if err != nil {
+			return errors.Wrapf(err, "load media group item %d", i)
+		}
+		defer func() {
+			if err := closer.Close(); err != nil {
+				h.logger.Error().Err(err).Msg("Failed to close asset file")
+			}
+		}()
+		switch f.Kind {
+		case MediaPhoto:
+			item := telegram.NewInputMediaPhoto(data)
+			item.Caption, item.ParseMode = ip.Interpolate(f.Caption), f.ParseMode
+			media[i] = item
+		case MediaVideo:
+			item := telegram.NewInputMediaVideo(data)
+			item.Caption, item.ParseMode = ip.Interpolate(f.Caption), f.ParseMode
+			media[i] = item
+		default:
+			return fmt.Errorf("unsupported media group item kind %q", f.Kind)
+		}
+	}
+	base := telegram.BaseChat{ChatID: uctx.ChatID().Int64()}
+	applySendOptions(ctx, upd, &base)
+	api, err := threadAPI(ctx, api)
+	if err != nil {
+		return err
+	}
+	// media group config of telegram library doesn't support these options
+	params := make(telegram.Params)
+	params.AddBool("protect_content", base.ProtectContent)
+	params.AddBool("allow_sending_without_reply", base.AllowSendingWithoutReply)
+	api = withParams(api, params)
+	cfg := telegram.NewMediaGroup(base.ChatID, media)
+	cfg.DisableNotification = base.DisableNotification
+	cfg.ReplyToMessageID = base.ReplyToMessageID
+	if _, err := api.SendMediaGroup(cfg); err != nil {
+		return errors.Wrap(err, "send media group")
+	}
+	return nil
+}
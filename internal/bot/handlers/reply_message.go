@@ -12,7 +12,7 @@ import (
 
 type (
 	// MessageModifier apply custom modifications to telegram message reply.
-	MessageModifier func(context.Context, *telegram.MessageConfig)
+	MessageModifier func(context.Context, *telegram.MessageConfig) error
 )
 
 var (
@@ -53,9 +53,17 @@ func (h *MessageReply) Handle(ctx context.Context, upd *telegram.Update, _ *tele
 
 	msg := telegram.NewMessage(int64(chatID), response)
 	for _, modifier := range h.modifiers {
-		modifier(ctx, &msg)
+		if err := modifier(ctx, &msg); err != nil {
+			return errors.Wrap(err, "modify message")
+		}
 	}
-	if _, err := h.bot.Send(msg); err != nil {
+	applySendOptions(ctx, upd, &msg.BaseChat)
+	msg.DisableWebPagePreview = sendOptionsFromCtx(ctx).DisableLinkPreview
+	bot, err := threadAPI(ctx, h.bot)
+	if err != nil {
+		return err
+	}
+	if _, err := bot.Send(msg); err != nil {
 		return errors.Wrap(err, "reply message")
 	}
 	return nil
@@ -71,7 +79,9 @@ func (h *MessageReply) Call(ctx context.Context, req api.Request) error {
 
 	msg := telegram.NewMessage(int64(req.ChatID), response)
 	for _, modifier := range h.modifiers {
-		modifier(ctx, &msg)
+		if err := modifier(ctx, &msg); err != nil {
+			return errors.Wrap(err, "modify message")
+		}
 	}
 	if _, err := h.bot.Send(msg); err != nil {
 		return errors.Wrap(err, "send message")
@@ -81,39 +91,73 @@ func (h *MessageReply) Call(ctx context.Context, req api.Request) error {
 
 // MessageWithKeyboard creates new message modifier to add
 // custom keyboard to message.
-func MessageWithKeyboard(keyboard [][]string) MessageModifier {
-	return func(ctx context.Context, msg *telegram.MessageConfig) {
+func MessageWithKeyboard(keyboard Keyboard, opts KeyboardOptions) MessageModifier {
+	return func(ctx context.Context, msg *telegram.MessageConfig) error {
 		if len(keyboard) == 0 {
-			return
+			return nil
 		}
-		buttons := make([][]telegram.KeyboardButton, len(keyboard))
-		for i, row := range keyboard {
-			buttonRow := make([]telegram.KeyboardButton, len(row))
-			for j, btn := range row {
-				buttonRow[j] = telegram.NewKeyboardButton(btn)
-			}
-			buttons[i] = buttonRow
+		u := UpdateContextFromCtx(ctx)
+		msg.ReplyMarkup = keyboard.telegramMarkup(u.Interpolator(), opts)
+		return nil
+	}
+}
+
+// MessageWithRemoveKeyboard creates new message modifier to remove
+// custom keyboard of the chat.
+func MessageWithRemoveKeyboard(selective bool) MessageModifier {
+	return func(ctx context.Context, msg *telegram.MessageConfig) error {
+		msg.ReplyMarkup = telegram.ReplyKeyboardRemove{RemoveKeyboard: true, Selective: selective}
+		return nil
+	}
+}
+
+// MessageWithForceReply creates new message modifier to show
+// reply interface to the user.
+func MessageWithForceReply(placeholder string, selective bool) MessageModifier {
+	return func(ctx context.Context, msg *telegram.MessageConfig) error {
+		u := UpdateContextFromCtx(ctx)
+		msg.ReplyMarkup = telegram.ForceReply{
+			ForceReply:            true,
+			InputFieldPlaceholder: u.Interpolator().Interpolate(placeholder),
+			Selective:             selective,
 		}
-		msg.ReplyMarkup = telegram.NewReplyKeyboard(buttons...)
+		return nil
 	}
 }
 
 // MessageWithInlineKeyboard creates new message modifier to add
 // custom inline keyboard to message.
 func MessageWithInlineKeyboard(keyboard InlineKeyboard) MessageModifier {
-	return func(ctx context.Context, msg *telegram.MessageConfig) {
+	return func(ctx context.Context, msg *telegram.MessageConfig) error {
 		if len(keyboard) == 0 {
-			return
+			return nil
 		}
 		u := UpdateContextFromCtx(ctx)
 		msg.ReplyMarkup = keyboard.telegramMarkup(u.Interpolator())
+		return nil
+	}
+}
+
+// MessageWithInlineKeyboardForeach creates new message modifier to add
+// inline keyboard with a button for each item of data array or state list.
+func MessageWithInlineKeyboardForeach(keyboard InlineKeyboardForeach) MessageModifier {
+	return func(ctx context.Context, msg *telegram.MessageConfig) error {
+		markup, err := keyboard.telegramMarkup(UpdateContextFromCtx(ctx))
+		if err != nil {
+			return errors.Wrap(err, "render inline keyboard")
+		}
+		if markup != nil {
+			msg.ReplyMarkup = *markup
+		}
+		return nil
 	}
 }
 
 // MessageWithParseMode creates new message modifier to set
 // custom parse mode for message.
 func MessageWithParseMode(mode string) MessageModifier {
-	return func(ctx context.Context, msg *telegram.MessageConfig) {
+	return func(ctx context.Context, msg *telegram.MessageConfig) error {
 		msg.ParseMode = mode
+		return nil
 	}
 }
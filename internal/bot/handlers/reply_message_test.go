@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/g4s8/openbots/internal/bot/updates"
+	"github.com/g4s8/openbots/pkg/secrets"
+	"github.com/g4s8/openbots/pkg/state"
+	"github.com/g4s8/openbots/pkg/types"
+	telegram "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+// replyText handles the update by message reply of the template
+// and returns the text of sent message.
+func replyText(t *testing.T, ctx context.Context, upd *telegram.Update, src string) string {
+	t.Helper()
+	api, fake := newTestAPI(t)
+	tpl, err := NewDefaultTemplate(src)
+	require.NoError(t, err)
+	ctx, err = NewUpdateContextProvider(secrets.Stub, state.NewMemory(nil)).NewContext(ctx, upd)
+	require.NoError(t, err)
+	require.NoError(t, NewMessageReply(api, tpl, zerolog.Nop()).Handle(ctx, upd, api))
+	calls := fake.requests()
+	require.Len(t, calls, 1)
+	return calls[0].params.Get("text")
+}
+
+func TestMessageReplyReaction(t *testing.T) {
+	chat := telegram.Chat{ID: 42, Type: "private"}
+	ctx := updates.ContextWithReaction(context.Background(), &updates.MessageReaction{
+		Chat: chat, MessageID: 7, User: &telegram.User{ID: 42},
+		New: []updates.ReactionType{{Type: "emoji", Emoji: "👍"}},
+	})
+	upd := &telegram.Update{Message: &telegram.Message{MessageID: 7, Chat: &chat, From: &telegram.User{ID: 42}}}
+	require.Equal(t, "r=👍", replyText(t, ctx, upd, "r=${reaction.emoji}"))
+}
+
+func TestMessageReplyPollAnswer(t *testing.T) {
+	ctx := updates.ContextWithPoll(context.Background(), &types.Poll{
+		ID: "p1", ChatID: 42, MessageID: 7, Name: "capital",
+		Options: []string{"Paris", "Rome"}, CorrectOption: 0,
+	})
+	upd := &telegram.Update{PollAnswer: &telegram.PollAnswer{
+		PollID: "p1", User: telegram.User{ID: 42}, OptionIDs: []int{1},
+	}}
+	require.Equal(t, "capital: Rome, correct=false",
+		replyText(t, ctx, upd, "${poll.name}: ${poll.option}, correct=${poll.correct}"))
+}
+
+func TestMessageReplyShared(t *testing.T) {
+	chat := telegram.Chat{ID: 42, Type: "private"}
+	ctx := updates.ContextWithShared(context.Background(), &updates.Shared{RequestID: 1, UserIDs: []int64{7, 8}})
+	upd := &telegram.Update{Message: &telegram.Message{MessageID: 3, Chat: &chat, From: &telegram.User{ID: 42}}}
+	require.Equal(t, "Invited users 7,8", replyText(t, ctx, upd, "Invited users ${shared.user_ids}"))
+}
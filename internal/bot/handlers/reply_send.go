@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/g4s8/openbots/pkg/types"
+	telegram "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+var _ types.Handler = (*ReplySend)(nil)
+
+// SendBuilder builds message for the chat using interpolator of update context.
+type SendBuilder func(chatID int64, ip Interpolator) (telegram.Chattable, error)
+
+// ReplySend sends a message built by SendBuilder, e.g. location or contact.
+type ReplySend struct {
+	name   string
+	build  SendBuilder
+	logger zerolog.Logger
+}
+
+// NewReplySend creates new ReplySend handler, name is used for logs and errors.
+func NewReplySend(name string, build SendBuilder, logger zerolog.Logger) *ReplySend {
+	return &ReplySend{
+		name:   name,
+		build:  build,
+		logger: logger.With().Str("handler", "reply_"+name).Logger(),
+	}
+}
+
+func (h *ReplySend) Handle(ctx context.Context, upd *telegram.Update, api *telegram.BotAPI) error {
+	uctx := UpdateContextFromCtx(ctx)
+	msg, err := h.build(uctx.ChatID().Int64(), uctx.Interpolator())
+	if err != nil {
+		return errors.Wrapf(err, "build %s", h.name)
+	}
+	base := telegram.BaseChat{ChatID: uctx.ChatID().Int64()}
+	applySendOptions(ctx, upd, &base)
+	api, err = threadAPI(ctx, api)
+	if err != nil {
+		return err
+	}
+	switch cfg := msg.(type) {
+	case telegram.LocationConfig:
+		cfg.BaseChat = base
+		msg = cfg
+	case telegram.VenueConfig:
+		cfg.BaseChat = base
+		msg = cfg
+	case telegram.ContactConfig:
+		cfg.BaseChat = base
+		msg = cfg
+	case telegram.DiceConfig:
+		cfg.BaseChat = base
+		msg = cfg
+	}
+	if _, err := api.Send(msg); err != nil {
+		return errors.Wrapf(err, "send %s", h.name)
+	}
+	return nil
+}
+
+// NewReplyLocation creates handler to send location, coordinates are interpolated.
+func NewReplyLocation(latitude, longitude string, logger zerolog.Logger) *ReplySend {
+	return NewReplySend("location", func(chatID int64, ip Interpolator) (telegram.Chattable, error) {
+		lat, lon, err := parseCoordinates(ip, latitude, longitude)
+		if err != nil {
+			return nil, err
+		}
+		return telegram.NewLocation(chatID, lat, lon), nil
+	}, logger)
+}
+
+// NewReplyVenue creates handler to send venue, all fields are interpolated.
+func NewReplyVenue(latitude, longitude, title, address string, logger zerolog.Logger) *ReplySend {
+	return NewReplySend("venue", func(chatID int64, ip Interpolator) (telegram.Chattable, error) {
+		lat, lon, err := parseCoordinates(ip, latitude, longitude)
+		if err != nil {
+			return nil, err
+		}
+		return telegram.NewVenue(chatID, ip.Interpolate(title), ip.Interpolate(address), lat, lon), nil
+	}, logger)
+}
+
+func parseCoordinates(ip Interpolator, latitude, longitude string) (float64, float64, error) {
+	lat, err := strconv.ParseFloat(ip.Interpolate(latitude), 64)
+	if err != nil {
+		return 0, 0, errors.Wrap(err, "parse latitude")
+	}
+	lon, err := strconv.ParseFloat(ip.Interpolate(longitude), 64)
+	if err != nil {
+		return 0, 0, errors.Wrap(err, "parse longitude")
+	}
+	return lat, lon, nil
+}
+
+// NewReplyContact creates handler to send contact, all fields are interpolated.
+func NewReplyContact(phone, firstName, lastName string, logger zerolog.Logger) *ReplySend {
+	return NewReplySend("contact", func(chatID int64, ip Interpolator) (telegram.Chattable, error) {
+		cfg := telegram.NewContact(chatID, ip.Interpolate(phone), ip.Interpolate(firstName))
+		cfg.LastName = ip.Interpolate(lastName)
+		return cfg, nil
+	}, logger)
+}
+
+// NewReplyDice creates handler to send dice, default dice is sent if emoji is empty.
+func NewReplyDice(emoji string, logger zerolog.Logger) *ReplySend {
+	return NewReplySend("dice", func(chatID int64, _ Interpolator) (telegram.Chattable, error) {
+		if emoji == "" {
+			return telegram.NewDice(chatID), nil
+		}
+		return telegram.NewDiceWithEmoji(chatID, emoji), nil
+	}, logger)
+}
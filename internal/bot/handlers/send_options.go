@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/g4s8/openbots/internal/bot/chat"
+	"github.com/g4s8/openbots/internal/bot/updates"
+	"github.com/g4s8/openbots/pkg/types"
+	telegram "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/pkg/errors"
+)
+
+// SendOptions are common options of messages sent by replies.
+type SendOptions struct {
+	// ReplyTo sends message as a reply to the update message.
+	ReplyTo             bool
+	DisableNotification bool
+	ProtectContent      bool
+	DisableLinkPreview  bool
+	// ThreadID is a forum topic ID, it's interpolated.
+	// Default is a topic of the update message.
+	ThreadID string
+}
+
+type sendOptionsKey struct{}
+
+// WithSendOptions wraps handler to send messages with options.
+func WithSendOptions(h types.Handler, opts SendOptions) types.Handler {
+	return &sendOptionsHandler{origin: h, opts: opts}
+}
+
+type sendOptionsHandler struct {
+	origin types.Handler
+	opts   SendOptions
+}
+
+func (h *sendOptionsHandler) Handle(ctx context.Context, upd *telegram.Update, api *telegram.BotAPI) error {
+	return h.origin.Handle(context.WithValue(ctx, sendOptionsKey{}, h.opts), upd, api)
+}
+
+func sendOptionsFromCtx(ctx context.Context) SendOptions {
+	opts, _ := ctx.Value(sendOptionsKey{}).(SendOptions)
+	return opts
+}
+
+// applySendOptions applies send options of context to the message sent to the update chat,
+// forum topic is applied by bot API of threadAPI.
+func applySendOptions(ctx context.Context, upd *telegram.Update, base *telegram.BaseChat) {
+	opts := sendOptionsFromCtx(ctx)
+	base.DisableNotification = opts.DisableNotification
+	base.ProtectContent = opts.ProtectContent
+	if upd == nil || !opts.ReplyTo {
+		return
+	}
+	msg := chat.Message(upd)
+	if msg == nil && upd.CallbackQuery != nil {
+		msg = upd.CallbackQuery.Message
+	}
+	if msg != nil {
+		base.ReplyToMessageID = msg.MessageID
+		base.AllowSendingWithoutReply = true
+	}
+}
+
+// threadAPI returns bot API which sends messages to the forum topic of send options,
+// or to the topic of the update message. It returns origin API if there is no topic.
+func threadAPI(ctx context.Context, api *telegram.BotAPI) (*telegram.BotAPI, error) {
+	thread := updates.ThreadFromCtx(ctx)
+	if opts := sendOptionsFromCtx(ctx); opts.ThreadID != "" {
+		val := UpdateContextFromCtx(ctx).Interpolator().Interpolate(opts.ThreadID)
+		id, err := strconv.Atoi(val)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid thread ID %q", val)
+		}
+		thread = id
+	}
+	params := make(telegram.Params)
+	params.AddNonZero("message_thread_id", thread)
+	return withParams(api, params), nil
+}
+
+// withParams returns a copy of bot API which adds parameters to all requests,
+// it's used for parameters not supported by telegram library, e.g. forum topic ID.
+func withParams(api *telegram.BotAPI, params telegram.Params) *telegram.BotAPI {
+	if len(params) == 0 {
+		return api
+	}
+	cp := *api
+	cp.Client = &paramsClient{origin: api.Client, params: params}
+	return &cp
+}
+
+// paramsClient adds parameters to request query,
+// telegram merges query parameters with request body parameters.
+type paramsClient struct {
+	origin telegram.HTTPClient
+	params telegram.Params
+}
+
+func (c *paramsClient) Do(req *http.Request) (*http.Response, error) {
+	query := req.URL.Query()
+	for k, v := range c.params {
+		query.Set(k, v)
+	}
+	req.URL.RawQuery = query.Encode()
+	return c.origin.Do(req)
+}
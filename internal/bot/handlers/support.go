@@ -0,0 +1,258 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/g4s8/openbots/pkg/state"
+	"github.com/g4s8/openbots/pkg/types"
+	telegram "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+const (
+	// supportSessionKey is a user state key of active support session.
+	supportSessionKey = "support.session"
+	// supportMessagePrefix is an operators chat state key prefix
+	// of relayed message, the value is a user chat ID.
+	supportMessagePrefix = "support.message."
+)
+
+// SupportMessageKey is an operators chat state key of relayed message,
+// its value is a user chat ID of the support session.
+func SupportMessageKey(msgID int) string {
+	return supportMessagePrefix + strconv.Itoa(msgID)
+}
+
+// SupportConfig configures support relay.
+type SupportConfig struct {
+	// Chat is operators chat ID.
+	Chat int64
+	// Topic is a forum topic ID of operators chat, optional.
+	Topic        int
+	CloseCommand string
+	// Header is sent to operators before the first message of session.
+	Header string
+	// Closed is sent to user when session is closed.
+	Closed string
+	// Serialize runs the task with updates of the chat one by one, it's
+	// used to update operators chat state from user chats, optional.
+	Serialize func(chatID types.ChatID, task func())
+}
+
+// Support relays messages between users and operators. Relayed messages
+// are tracked in operators chat state to send operator replies back to users.
+type Support struct {
+	cfg    SupportConfig
+	cp     types.ContextProvider
+	sp     types.StateProvider
+	logger zerolog.Logger
+}
+
+func NewSupport(cfg SupportConfig, cp types.ContextProvider, sp types.StateProvider, logger zerolog.Logger) *Support {
+	return &Support{
+		cfg:    cfg,
+		cp:     cp,
+		sp:     sp,
+		logger: logger.With().Str("handler", "support").Logger(),
+	}
+}
+
+// UserHandler relays user messages to operators.
+func (s *Support) UserHandler() types.Handler {
+	return supportHandler(s.handleUser)
+}
+
+// OperatorHandler relays operator replies to users.
+func (s *Support) OperatorHandler() types.Handler {
+	return supportHandler(s.handleOperator)
+}
+
+type supportHandler func(context.Context, *telegram.Update, *telegram.BotAPI) error
+
+func (f supportHandler) Handle(ctx context.Context, upd *telegram.Update, api *telegram.BotAPI) error {
+	return f(ctx, upd, api)
+}
+
+func (s *Support) handleUser(ctx context.Context, upd *telegram.Update, api *telegram.BotAPI) error {
+	msg := upd.Message
+	user := types.ChatID(msg.Chat.ID)
+	if msg.IsCommand() && msg.Command() == s.cfg.CloseCommand {
+		return s.close(ctx, api, user, false)
+	}
+
+	st := state.NewUserState()
+	defer st.Close()
+	if err := s.sp.Load(ctx, user, st); err != nil {
+		return errors.Wrap(err, "load user state")
+	}
+	if _, ok := st.Get(supportSessionKey); !ok {
+		header := UpdateContextFromCtx(ctx).Interpolator().Interpolate(s.cfg.Header)
+		id, err := s.send(api, "sendMessage", s.operatorParams(telegram.Params{"text": header}))
+		if err != nil {
+			return errors.Wrap(err, "send session header")
+		}
+		if err := s.serialize(func() error { return s.track(ctx, id, user) }); err != nil {
+			return err
+		}
+		st.Set(supportSessionKey, strconv.Itoa(msg.Date))
+		if err := s.sp.Update(ctx, user, st); err != nil {
+			return errors.Wrap(err, "update user state")
+		}
+	}
+
+	s.logger.Debug().Str("user", user.String()).Int("message_id", msg.MessageID).Msg("Relay user message")
+	id, err := s.send(api, "copyMessage", s.operatorParams(copyParams(msg)))
+	if err != nil {
+		return errors.Wrap(err, "copy user message")
+	}
+	return s.serialize(func() error { return s.track(ctx, id, user) })
+}
+
+func (s *Support) handleOperator(ctx context.Context, upd *telegram.Update, api *telegram.BotAPI) error {
+	msg := upd.Message
+	st := state.NewUserState()
+	defer st.Close()
+	if err := s.sp.Load(ctx, types.ChatID(s.cfg.Chat), st); err != nil {
+		return errors.Wrap(err, "load operators state")
+	}
+	val, ok := st.Get(SupportMessageKey(msg.ReplyToMessage.MessageID))
+	if !ok {
+		// not a reply to relayed message
+		return nil
+	}
+	id, err := strconv.ParseInt(val, 10, 64)
+	if err != nil {
+		return errors.Wrapf(err, "invalid support user chat %q", val)
+	}
+	user := types.ChatID(id)
+	if msg.IsCommand() && msg.Command() == s.cfg.CloseCommand {
+		return s.close(ctx, api, user, true)
+	}
+
+	s.logger.Debug().Str("user", user.String()).Int("message_id", msg.MessageID).Msg("Relay operator reply")
+	params := copyParams(msg)
+	params.AddNonZero64("chat_id", user.Int64())
+	if _, err := s.send(api, "copyMessage", params); err != nil {
+		return errors.Wrap(err, "copy operator message")
+	}
+	return nil
+}
+
+// close support session: reset user context, forget relayed messages
+// of the session and notify user and operators. The session is closed
+// by operators if the update is from operators chat.
+func (s *Support) close(ctx context.Context, api *telegram.BotAPI, user types.ChatID, operators bool) error {
+	// it's a pending context if the update is sent by the user, or origin
+	// context if it's closed by the operator, both are saved
+	if err := s.cp.UserContext(user).Reset(ctx); err != nil {
+		return errors.Wrap(err, "reset user context")
+	}
+	st := state.NewUserState()
+	defer st.Close()
+	if err := s.sp.Load(ctx, user, st); err != nil {
+		return errors.Wrap(err, "load user state")
+	}
+	st.Delete(supportSessionKey)
+	if err := s.sp.Update(ctx, user, st); err != nil {
+		return errors.Wrap(err, "update user state")
+	}
+	untrack := func() error { return s.untrack(ctx, user) }
+	var err error
+	if operators {
+		// the update of operators chat is already serialized
+		err = untrack()
+	} else {
+		err = s.serialize(untrack)
+	}
+	if err != nil {
+		return err
+	}
+
+	s.logger.Debug().Str("user", user.String()).Msg("Close support session")
+	if s.cfg.Closed != "" {
+		if _, err := api.Send(telegram.NewMessage(user.Int64(), s.cfg.Closed)); err != nil {
+			return errors.Wrap(err, "send session closed message")
+		}
+	}
+	text := "Support session of " + user.String() + " is closed"
+	if _, err := s.send(api, "sendMessage", s.operatorParams(telegram.Params{"text": text})); err != nil {
+		return errors.Wrap(err, "send session closed message to operators")
+	}
+	return nil
+}
+
+// serialize the task with updates of operators chat,
+// since operators chat state is changed by many user chats.
+func (s *Support) serialize(task func() error) error {
+	if s.cfg.Serialize == nil {
+		return task()
+	}
+	var err error
+	s.cfg.Serialize(types.ChatID(s.cfg.Chat), func() { err = task() })
+	return err
+}
+
+// track relayed message in operators chat state.
+func (s *Support) track(ctx context.Context, msgID int, user types.ChatID) error {
+	chat := types.ChatID(s.cfg.Chat)
+	st := state.NewUserState()
+	defer st.Close()
+	if err := s.sp.Load(ctx, chat, st); err != nil {
+		return errors.Wrap(err, "load operators state")
+	}
+	st.Set(SupportMessageKey(msgID), user.String())
+	if err := s.sp.Update(ctx, chat, st); err != nil {
+		return errors.Wrap(err, "update operators state")
+	}
+	return nil
+}
+
+// untrack all relayed messages of the user in operators chat state.
+func (s *Support) untrack(ctx context.Context, user types.ChatID) error {
+	chat := types.ChatID(s.cfg.Chat)
+	st := state.NewUserState()
+	defer st.Close()
+	if err := s.sp.Load(ctx, chat, st); err != nil {
+		return errors.Wrap(err, "load operators state")
+	}
+	for key, val := range st.Map() {
+		if strings.HasPrefix(key, supportMessagePrefix) && val == user.String() {
+			st.Delete(key)
+		}
+	}
+	if err := s.sp.Update(ctx, chat, st); err != nil {
+		return errors.Wrap(err, "update operators state")
+	}
+	return nil
+}
+
+func (s *Support) operatorParams(params telegram.Params) telegram.Params {
+	params.AddNonZero64("chat_id", s.cfg.Chat)
+	params.AddNonZero("message_thread_id", s.cfg.Topic)
+	return params
+}
+
+func copyParams(msg *telegram.Message) telegram.Params {
+	params := make(telegram.Params)
+	params.AddNonZero64("from_chat_id", msg.Chat.ID)
+	params.AddNonZero("message_id", msg.MessageID)
+	return params
+}
+
+// send raw request, since telegram library doesn't support forum topics,
+// and returns sent message ID.
+func (s *Support) send(api *telegram.BotAPI, method string, params telegram.Params) (int, error) {
+	resp, err := api.MakeRequest(method, params)
+	if err != nil {
+		return 0, err
+	}
+	var res telegram.MessageID
+	if err := json.Unmarshal(resp.Result, &res); err != nil {
+		return 0, errors.Wrap(err, "decode message ID")
+	}
+	return res.MessageID, nil
+}
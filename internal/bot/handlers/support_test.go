@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"testing"
+
+	botctx "github.com/g4s8/openbots/pkg/context"
+	"github.com/g4s8/openbots/pkg/state"
+	"github.com/g4s8/openbots/pkg/types"
+	telegram "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSupportRelay(t *testing.T) {
+	const (
+		operators = int64(-100)
+		userID    = int64(42)
+	)
+	api, fake := newTestAPI(t)
+	sp := state.NewMemory(nil)
+	var serialized []types.ChatID
+	support := NewSupport(SupportConfig{
+		Chat:         operators,
+		Topic:        7,
+		CloseCommand: "close",
+		Header:       "New session",
+		Closed:       "Session closed",
+		Serialize: func(chatID types.ChatID, task func()) {
+			serialized = append(serialized, chatID)
+			task()
+		},
+	}, botctx.NewMemoryProvider(), sp, zerolog.Nop())
+	userMessage := func(id int, text string) *telegram.Update {
+		return &telegram.Update{Message: &telegram.Message{
+			MessageID: id, Date: 1, Text: text,
+			Chat: &telegram.Chat{ID: userID, Type: "private"},
+			From: &telegram.User{ID: userID},
+		}}
+	}
+	operatorReply := func(id, replyTo int, text string) *telegram.Update {
+		msg := &telegram.Message{
+			MessageID: id, Text: text,
+			Chat:           &telegram.Chat{ID: operators, Type: "supergroup"},
+			From:           &telegram.User{ID: 7},
+			ReplyToMessage: &telegram.Message{MessageID: replyTo},
+		}
+		if text[0] == '/' {
+			msg.Entities = []telegram.MessageEntity{{Type: "bot_command", Length: len(text)}}
+		}
+		return &telegram.Update{Message: msg}
+	}
+
+	upd := userMessage(10, "help")
+	require.NoError(t, support.UserHandler().Handle(updateContext(t, upd, sp), upd, api))
+	calls := fake.requests()
+	require.Len(t, calls, 2, "header and user message")
+	require.Equal(t, "sendMessage", calls[0].method)
+	require.Equal(t, "-100", calls[0].params.Get("chat_id"))
+	require.Equal(t, "7", calls[0].params.Get("message_thread_id"))
+	require.Equal(t, "New session", calls[0].params.Get("text"))
+	require.Equal(t, "copyMessage", calls[1].method)
+	require.Equal(t, "42", calls[1].params.Get("from_chat_id"))
+	require.Equal(t, "10", calls[1].params.Get("message_id"))
+	require.Equal(t, map[string]string{
+		SupportMessageKey(101): "42",
+		SupportMessageKey(102): "42",
+	}, chatState(t, sp, -100))
+	require.Equal(t, []types.ChatID{-100, -100}, serialized, "operators state is updated in operators chat queue")
+	serialized = nil
+
+	upd = userMessage(11, "are you there?")
+	require.NoError(t, support.UserHandler().Handle(updateContext(t, upd, sp), upd, api))
+	calls = fake.requests()
+	require.Len(t, calls, 1, "header is sent once per session")
+	require.Equal(t, "copyMessage", calls[0].method)
+	require.Equal(t, []types.ChatID{-100}, serialized)
+	serialized = nil
+
+	upd = operatorReply(20, 102, "hello")
+	require.NoError(t, support.OperatorHandler().Handle(updateContext(t, upd, sp), upd, api))
+	calls = fake.requests()
+	require.Len(t, calls, 1)
+	require.Equal(t, "copyMessage", calls[0].method)
+	require.Equal(t, "42", calls[0].params.Get("chat_id"))
+	require.Equal(t, "-100", calls[0].params.Get("from_chat_id"))
+	require.Equal(t, "20", calls[0].params.Get("message_id"))
+
+	upd = operatorReply(21, 5, "not relayed")
+	require.NoError(t, support.OperatorHandler().Handle(updateContext(t, upd, sp), upd, api))
+	require.Empty(t, fake.requests(), "reply to other message")
+
+	upd = operatorReply(22, 101, "/close")
+	require.NoError(t, support.OperatorHandler().Handle(updateContext(t, upd, sp), upd, api))
+	calls = fake.requests()
+	require.Len(t, calls, 2)
+	require.Equal(t, "42", calls[0].params.Get("chat_id"))
+	require.Equal(t, "Session closed", calls[0].params.Get("text"))
+	require.Equal(t, "-100", calls[1].params.Get("chat_id"))
+	require.Empty(t, chatState(t, sp, -100), "relayed messages are forgotten")
+	require.NotContains(t, chatState(t, sp, 42), supportSessionKey)
+	require.Empty(t, serialized, "updates of operators chat are not serialized again")
+}
@@ -2,10 +2,8 @@ package handlers
 
 import (
 	"bytes"
-	"fmt"
 	"text/template"
 
-	"github.com/g4s8/openbots/internal/bot/interpolator"
 	"github.com/g4s8/openbots/pkg/types"
 	telegram "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"github.com/pkg/errors"
@@ -16,6 +14,12 @@ type templateContext struct {
 	State   map[string]string
 	Secrets map[string]string
 	Data    any
+	Match   map[string]string
+	Args    map[string]string
+	// Callback parameters of callback trigger.
+	Callback map[string]string
+	// interpolator of the update context for default templates.
+	interpolator Interpolator
 }
 
 func newTemplateContext(upd *telegram.Update, state map[string]string, secrets map[string]types.Secret, Data any) *templateContext {
@@ -58,32 +62,7 @@ type defaultTemplate struct {
 }
 
 func (t *defaultTemplate) Format(ctx *templateContext) (string, error) {
-	secrets := make(map[string]types.Secret, len(ctx.Secrets))
-	for k, v := range ctx.Secrets {
-		secrets[k] = types.Secret(v)
-	}
-	var opts []interpolator.InterpolatorOp
-	if ctx.Update != nil {
-		opts = append(opts, interpolator.WithUpdate(ctx.Update))
-	}
-	if ctx.State != nil {
-		opts = append(opts, interpolator.WithState(ctx.State))
-	}
-	var data map[string]string
-	if d, ok := ctx.Data.(map[string]string); ok {
-		data = d
-	} else if d, ok := ctx.Data.(map[string]interface{}); ok {
-		data = make(map[string]string, len(d))
-		for k, v := range d {
-			data[k] = fmt.Sprintf("%v", v)
-		}
-	}
-	if data != nil {
-		opts = append(opts, interpolator.WithData(data))
-	}
-	intp := interpolator.NewWithOps(opts...)
-	processed := intp.Interpolate(t.src)
-	return processed, nil
+	return ctx.interpolator.Interpolate(t.src), nil
 }
 
 type goTemplate struct {
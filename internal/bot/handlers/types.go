@@ -2,11 +2,16 @@ package handlers
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"strconv"
 	"strings"
 
+	"github.com/g4s8/openbots/internal/bot/chat"
 	"github.com/g4s8/openbots/internal/bot/data"
 	"github.com/g4s8/openbots/internal/bot/interpolator"
+	"github.com/g4s8/openbots/internal/bot/match"
+	"github.com/g4s8/openbots/internal/bot/updates"
 	"github.com/g4s8/openbots/pkg/state"
 	"github.com/g4s8/openbots/pkg/types"
 	telegram "github.com/go-telegram-bot-api/telegram-bot-api/v5"
@@ -22,6 +27,13 @@ type UpdateContext struct {
 	state   map[string]string
 	secrets map[string]types.Secret
 	data    *types.DataContainer
+	match   match.Values
+	// reaction of message reaction update.
+	reaction *updates.MessageReaction
+	// poll of poll answer update.
+	poll *types.Poll
+	// shared users or chat of the update message.
+	shared *updates.Shared
 }
 
 func (c *UpdateContext) ChatID() types.ChatID {
@@ -37,27 +49,137 @@ func (c *UpdateContext) templateContext() *templateContext {
 	if c.data != nil {
 		data = c.data.Get()
 	}
-	return newTemplateContext(c.upd, c.state, c.secrets, data)
+	tctx := newTemplateContext(c.upd, c.state, c.secrets, data)
+	tctx.Match = c.match.Get(match.Groups)
+	tctx.Args = c.commandArgs()
+	tctx.Callback = c.match.Get(match.Callback)
+	tctx.interpolator = c.Interpolator()
+	return tctx
+}
+
+// commandArgs returns positional and named command arguments.
+func (c *UpdateContext) commandArgs() map[string]string {
+	if c.upd == nil {
+		return nil
+	}
+	msg := chat.Message(c.upd)
+	if msg == nil || !msg.IsCommand() {
+		return nil
+	}
+	res := make(map[string]string)
+	for i, arg := range strings.Fields(msg.CommandArguments()) {
+		res[strconv.Itoa(i)] = arg
+	}
+	for k, v := range c.match.Get(match.Args) {
+		res[k] = v
+	}
+	return res
 }
 
 func (c *UpdateContext) Interpolator() Interpolator {
+	return interpolator.NewWithOps(c.interpolatorOps()...)
+}
+
+// interpolatorWith returns interpolator with current state and secrets,
+// since state could be changed by previous handlers of the update.
+func (c *UpdateContext) interpolatorWith(state map[string]string, secrets map[string]types.Secret) Interpolator {
+	res := *c
+	res.state = state
+	res.secrets = secrets
+	return res.Interpolator()
+}
+
+// ItemInterpolator returns interpolator with item of iterated data.
+func (c *UpdateContext) ItemInterpolator(index int, item any) Interpolator {
+	opts := append(c.interpolatorOps(), interpolator.WithItem(index, item))
+	return interpolator.NewWithOps(opts...)
+}
+
+// PageInterpolator returns interpolator with page of paginated list.
+func (c *UpdateContext) PageInterpolator(page, pages int) Interpolator {
+	opts := append(c.interpolatorOps(), interpolator.WithPage(page, pages))
+	return interpolator.NewWithOps(opts...)
+}
+
+// PageItemInterpolator returns interpolator with item of paginated list.
+func (c *UpdateContext) PageItemInterpolator(page, pages, index int, item any) Interpolator {
+	opts := append(c.interpolatorOps(), interpolator.WithPage(page, pages), interpolator.WithItem(index, item))
+	return interpolator.NewWithOps(opts...)
+}
+
+// Items returns list of items from loaded data by path,
+// where path is `data` for root array or `data.<key>` for array field of root object,
+// or from state by `state.<key>` path, where state value is a JSON array or comma-separated list.
+func (c *UpdateContext) Items(path string) ([]any, error) {
+	if key, ok := strings.CutPrefix(path, "state."); ok {
+		return stateItems(c.state[key])
+	}
+	var val any
+	if c.data != nil {
+		val = c.data.Get()
+	}
+	if path != "data" {
+		key, ok := strings.CutPrefix(path, "data.")
+		if !ok {
+			return nil, errors.Errorf("invalid data path %q", path)
+		}
+		m, ok := val.(map[string]any)
+		if !ok {
+			return nil, errors.Errorf("data is not an object: %T", val)
+		}
+		val = m[key]
+	}
+	items, ok := val.([]any)
+	if !ok {
+		return nil, errors.Errorf("data %q is not an array: %T", path, val)
+	}
+	return items, nil
+}
+
+func stateItems(val string) ([]any, error) {
+	if val == "" {
+		return nil, nil
+	}
+	if strings.HasPrefix(val, "[") {
+		var items []any
+		if err := json.Unmarshal([]byte(val), &items); err != nil {
+			return nil, errors.Wrap(err, "decode state list")
+		}
+		return items, nil
+	}
+	parts := strings.Split(val, ",")
+	items := make([]any, len(parts))
+	for i, p := range parts {
+		items[i] = strings.TrimSpace(p)
+	}
+	return items, nil
+}
+
+func (c *UpdateContext) interpolatorOps() []interpolator.InterpolatorOp {
 	opts := []interpolator.InterpolatorOp{
 		interpolator.WithState(c.state),
 		interpolator.WithSecrets(c.secrets),
 		interpolator.WithUpdate(c.upd),
+		interpolator.WithMatch(c.match),
+		interpolator.WithReaction(c.reaction),
+		interpolator.WithPoll(c.poll),
+		interpolator.WithShared(c.shared),
 	}
 	var data any
 	if c.data != nil {
 		data = c.data.Get()
 	}
-	if dataMap, ok := data.(map[string]any); ok {
+	switch dataMap := data.(type) {
+	case map[string]string:
+		opts = append(opts, interpolator.WithData(dataMap))
+	case map[string]any:
 		m := make(map[string]string, len(dataMap))
 		for k, v := range dataMap {
 			m[k] = fmt.Sprintf("%v", v)
 		}
 		opts = append(opts, interpolator.WithData(m))
 	}
-	return interpolator.NewWithOps(opts...)
+	return opts
 }
 
 func (c *UpdateContext) String() string {
@@ -74,9 +196,10 @@ type updateContextKey struct{}
 
 func UpdateContextFromCtx(ctx context.Context) *UpdateContext {
 	if uctx, ok := ctx.Value(updateContextKey{}).(*UpdateContext); ok {
-		res := uctx
+		res := *uctx
 		res.data = data.FromCtx(ctx)
-		return res
+		res.match = match.FromCtx(ctx)
+		return &res
 	}
 	return &UpdateContext{}
 }
@@ -106,9 +229,12 @@ func (cp *UpdateContextProvider) NewContext(ctx context.Context, upd *telegram.U
 		return nil, errors.Wrap(err, "get secrets")
 	}
 	c := &UpdateContext{
-		upd:     upd,
-		state:   state.Map(),
-		secrets: secretMap,
+		upd:      upd,
+		state:    state.Map(),
+		secrets:  secretMap,
+		reaction: updates.ReactionFromCtx(ctx),
+		poll:     updates.PollFromCtx(ctx),
+		shared:   updates.SharedFromCtx(ctx),
 	}
 	return context.WithValue(ctx, updateContextKey{}, c), nil
 }
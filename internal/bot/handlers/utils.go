@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"github.com/g4s8/openbots/internal/bot/chat"
 	"github.com/g4s8/openbots/pkg/types"
 	telegram "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
@@ -15,8 +16,5 @@ func ChatID(upd *telegram.Update) types.ChatID {
 }
 
 func rawChatID(upd *telegram.Update) int64 {
-	if chat := upd.FromChat(); chat != nil {
-		return chat.ID
-	}
-	return -1
+	return chat.ID(upd)
 }
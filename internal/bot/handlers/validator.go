@@ -83,6 +83,10 @@ func (v *Validator) Handle(ctx context.Context, upd *telegram.Update, api *teleg
 			v.logger.Debug().Err(err).Msg("Validation failed")
 			if upd.Message != nil && upd.Message.Chat != nil {
 				msg := telegram.NewMessage(upd.Message.Chat.ID, v.errMessage)
+				api, err := threadAPI(ctx, api)
+				if err != nil {
+					return err
+				}
 				if _, err := api.Send(msg); err != nil {
 					return fmt.Errorf("failed to send validation error message: %w", err)
 				}
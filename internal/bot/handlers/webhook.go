@@ -13,7 +13,6 @@ import (
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog"
 
-	"github.com/g4s8/openbots/internal/bot/interpolator"
 	"github.com/g4s8/openbots/pkg/state"
 	"github.com/g4s8/openbots/pkg/types"
 )
@@ -67,7 +66,7 @@ func (h *Webhook) Handle(ctx context.Context, upd *telegram.Update, _ *telegram.
 	if err != nil {
 		return errors.Wrap(err, "get secrets")
 	}
-	interpolator := interpolator.New(state.Map(), secretMap, upd)
+	interpolator := UpdateContextFromCtx(ctx).interpolatorWith(state.Map(), secretMap)
 	values := make(map[string]string, len(h.data))
 	for k, v := range h.data {
 		values[k] = interpolator.Interpolate(v)
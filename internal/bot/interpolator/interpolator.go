@@ -3,9 +3,13 @@ package interpolator
 import (
 	"fmt"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
 
+	"github.com/g4s8/openbots/internal/bot/chat"
+	"github.com/g4s8/openbots/internal/bot/match"
+	"github.com/g4s8/openbots/internal/bot/updates"
 	"github.com/g4s8/openbots/pkg/types"
 	telegram "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
@@ -13,10 +17,16 @@ import (
 var emptyMap = make(map[string]string)
 
 type Interpolator struct {
-	state   map[string]string
-	secrets map[string]types.Secret
-	upd     *telegram.Update
-	data    map[string]string
+	state    map[string]string
+	secrets  map[string]types.Secret
+	upd      *telegram.Update
+	data     map[string]string
+	match    match.Values
+	item     map[string]string
+	page     map[string]string
+	reaction *updates.MessageReaction
+	poll     *types.Poll
+	shared   *updates.Shared
 }
 
 type InterpolatorOp func(*Interpolator)
@@ -45,6 +55,79 @@ func WithData(data map[string]string) InterpolatorOp {
 	}
 }
 
+// WithMatch adds values captured by event filters, each value is available
+// by `<namespace>.<key>` name.
+func WithMatch(vals match.Values) InterpolatorOp {
+	return func(i *Interpolator) {
+		i.match = vals
+	}
+}
+
+// WithReaction adds message reaction fields, they are available
+// by `reaction.<field>` names.
+func WithReaction(r *updates.MessageReaction) InterpolatorOp {
+	return func(i *Interpolator) {
+		i.reaction = r
+	}
+}
+
+// WithPoll adds the poll of poll answer update, poll fields
+// are available by `poll.<field>` names.
+func WithPoll(poll *types.Poll) InterpolatorOp {
+	return func(i *Interpolator) {
+		i.poll = poll
+	}
+}
+
+// WithShared adds users or chat shared by request keyboard button,
+// they are available by `shared.<field>` names.
+func WithShared(s *updates.Shared) InterpolatorOp {
+	return func(i *Interpolator) {
+		i.shared = s
+	}
+}
+
+// WithItem adds current item of iterated data, the item is available
+// by `item` name, its fields by `item.<key>` name and its index by `index` name.
+func WithItem(index int, item any) InterpolatorOp {
+	return func(i *Interpolator) {
+		i.item = map[string]string{"index": strconv.Itoa(index)}
+		if m, ok := item.(map[string]any); ok {
+			for k, v := range m {
+				i.item["item."+k] = fmt.Sprintf("%v", v)
+			}
+		} else {
+			i.item["item"] = fmt.Sprintf("%v", item)
+		}
+	}
+}
+
+// replyTo adds fields of the message which is replied by update message.
+func replyTo(msg *telegram.Message, data map[string]string) {
+	data["message.reply_to.id"] = strconv.Itoa(msg.MessageID)
+	data["message.reply_to.text"] = msg.Text
+	data["message.reply_to.caption"] = msg.Caption
+	if msg.From != nil {
+		data["message.reply_to.from.id"] = strconv.FormatInt(msg.From.ID, 10)
+		data["message.reply_to.from.username"] = msg.From.UserName
+		data["message.reply_to.from.first_name"] = msg.From.FirstName
+	}
+	if f := msg.ForwardFrom; f != nil {
+		data["message.reply_to.forward_from.id"] = strconv.FormatInt(f.ID, 10)
+	}
+}
+
+// WithPage adds current page number (starting from 1) and total number of pages
+// of paginated list, they are available by `page` and `pages` names.
+func WithPage(page, pages int) InterpolatorOp {
+	return func(i *Interpolator) {
+		i.page = map[string]string{
+			"page":  strconv.Itoa(page),
+			"pages": strconv.Itoa(pages),
+		}
+	}
+}
+
 // NewWithOps interpolator with options.
 func NewWithOps(ops ...InterpolatorOp) *Interpolator {
 	i := &Interpolator{}
@@ -67,12 +150,49 @@ func New(state map[string]string, secrets map[string]types.Secret, upd *telegram
 func (i *Interpolator) expander() func(string) string {
 	data := make(map[string]string)
 	if upd := i.upd; upd != nil {
-		if msg := upd.Message; msg != nil {
+		if msg := chat.Message(upd); msg != nil {
 			data["message.id"] = strconv.Itoa(msg.MessageID)
 			data["message.text"] = msg.Text
-			data["message.from.id"] = strconv.FormatInt(msg.From.ID, 10)
+			if msg.EditDate != 0 {
+				data["message.edit_date"] = strconv.Itoa(msg.EditDate)
+			}
+			if msg.From != nil {
+				data["message.from.id"] = strconv.FormatInt(msg.From.ID, 10)
+			}
+			if msg.IsCommand() {
+				args := msg.CommandArguments()
+				data["message.command"] = msg.Command()
+				data["message.args"] = args
+				for i, arg := range strings.Fields(args) {
+					data["message.args."+strconv.Itoa(i)] = arg
+				}
+			}
+			messageMedia(msg, data)
+			if f := msg.ForwardFrom; f != nil {
+				data["message.forward_from.id"] = strconv.FormatInt(f.ID, 10)
+			}
+			if r := msg.ReplyToMessage; r != nil {
+				replyTo(r, data)
+			}
+			if d := msg.WebAppData; d != nil {
+				data["webapp.data"] = d.Data
+				data["webapp.button_text"] = d.ButtonText
+			}
+		}
+		if cb := upd.CallbackQuery; cb != nil {
+			data["callback.data"] = cb.Data
+		}
+		if q := upd.InlineQuery; q != nil {
+			data["inline.id"] = q.ID
+			data["inline.query"] = q.Query
+			data["inline.offset"] = q.Offset
 		}
-		if chat := upd.FromChat(); chat != nil {
+		if r := upd.ChosenInlineResult; r != nil {
+			data["inline.result_id"] = r.ResultID
+			data["inline.query"] = r.Query
+			data["inline.message_id"] = r.InlineMessageID
+		}
+		if chat := chat.FromUpdate(upd); chat != nil {
 			data["chat.id"] = strconv.FormatInt(chat.ID, 10)
 			data["chat.type"] = chat.Type
 			data["chat.title"] = chat.Title
@@ -80,7 +200,22 @@ func (i *Interpolator) expander() func(string) string {
 			data["chat.last_name"] = chat.LastName
 			data["chat.username"] = chat.UserName
 		}
-		if user := upd.SentFrom(); user != nil {
+		if m := upd.MyChatMember; m != nil {
+			chatMember(m, data)
+		}
+		if m := upd.ChatMember; m != nil {
+			chatMember(m, data)
+		}
+		if a := upd.PollAnswer; a != nil {
+			pollAnswer(a, i.poll, data)
+		}
+		if r := upd.ChatJoinRequest; r != nil {
+			data["join_request.bio"] = r.Bio
+			if r.InviteLink != nil {
+				data["join_request.invite_link"] = r.InviteLink.InviteLink
+			}
+		}
+		if user := chat.Sender(upd); user != nil {
 			data["user.id"] = strconv.FormatInt(user.ID, 10)
 			data["user.is_bot"] = strconv.FormatBool(user.IsBot)
 			data["user.first_name"] = user.FirstName
@@ -90,9 +225,26 @@ func (i *Interpolator) expander() func(string) string {
 		}
 	}
 
+	if r := i.reaction; r != nil {
+		messageReaction(r, data)
+	}
+	if s := i.shared; s != nil {
+		sharedFields(s, data)
+	}
 	for k, v := range i.data {
 		data["data."+k] = v
 	}
+	for ns, vals := range i.match {
+		for k, v := range vals {
+			data[ns+"."+k] = v
+		}
+	}
+	for k, v := range i.item {
+		data[k] = v
+	}
+	for k, v := range i.page {
+		data[k] = v
+	}
 
 	return func(text string) string {
 		if strings.HasPrefix(text, "state.") {
@@ -111,6 +263,130 @@ func (i *Interpolator) expander() func(string) string {
 	}
 }
 
+// messageReaction puts message reaction fields to data,
+// `reaction.emoji` is the first new reaction.
+func messageReaction(r *updates.MessageReaction, data map[string]string) {
+	data["reaction.message_id"] = strconv.Itoa(r.MessageID)
+	if len(r.New) > 0 {
+		data["reaction.emoji"] = r.New[0].String()
+	}
+	data["reaction.new"] = reactionList(r.New)
+	data["reaction.old"] = reactionList(r.Old)
+}
+
+// sharedFields puts shared users and chat fields to data,
+// `shared.user_id` is the first shared user.
+func sharedFields(s *updates.Shared, data map[string]string) {
+	data["shared.request_id"] = strconv.Itoa(s.RequestID)
+	ids := make([]string, len(s.UserIDs))
+	for i, id := range s.UserIDs {
+		ids[i] = strconv.FormatInt(id, 10)
+	}
+	if len(ids) > 0 {
+		data["shared.user_id"] = ids[0]
+	}
+	data["shared.user_ids"] = strings.Join(ids, ",")
+	if s.ChatID != 0 {
+		data["shared.chat_id"] = strconv.FormatInt(s.ChatID, 10)
+	}
+}
+
+func reactionList(rs []updates.ReactionType) string {
+	res := make([]string, len(rs))
+	for i, r := range rs {
+		res[i] = r.String()
+	}
+	return strings.Join(res, ",")
+}
+
+// pollAnswer puts poll answer fields to data, `poll.option_id` and `poll.option`
+// are the first chosen option index and text.
+func pollAnswer(a *telegram.PollAnswer, poll *types.Poll, data map[string]string) {
+	data["poll.id"] = a.PollID
+	ids := make([]string, len(a.OptionIDs))
+	for i, id := range a.OptionIDs {
+		ids[i] = strconv.Itoa(id)
+	}
+	data["poll.option_ids"] = strings.Join(ids, ",")
+	if len(ids) > 0 {
+		data["poll.option_id"] = ids[0]
+	}
+	if poll == nil {
+		return
+	}
+	data["poll.name"] = poll.Name
+	data["poll.chat_id"] = poll.ChatID.String()
+	data["poll.message_id"] = strconv.Itoa(poll.MessageID)
+	if len(a.OptionIDs) > 0 && a.OptionIDs[0] < len(poll.Options) {
+		data["poll.option"] = poll.Options[a.OptionIDs[0]]
+	}
+	if poll.Quiz() {
+		data["poll.correct"] = strconv.FormatBool(slices.Contains(a.OptionIDs, poll.CorrectOption))
+		data["poll.correct_option_id"] = strconv.Itoa(poll.CorrectOption)
+	}
+}
+
+// chatMember puts chat member update fields to data.
+func chatMember(m *telegram.ChatMemberUpdated, data map[string]string) {
+	data["member.status"] = m.NewChatMember.Status
+	data["member.old_status"] = m.OldChatMember.Status
+	if user := m.NewChatMember.User; user != nil {
+		data["member.user.id"] = strconv.FormatInt(user.ID, 10)
+		data["member.user.is_bot"] = strconv.FormatBool(user.IsBot)
+		data["member.user.first_name"] = user.FirstName
+		data["member.user.last_name"] = user.LastName
+		data["member.user.username"] = user.UserName
+	}
+	if m.InviteLink != nil {
+		data["member.invite_link"] = m.InviteLink.InviteLink
+	}
+}
+
+// messageMedia puts message attachments fields to data.
+func messageMedia(msg *telegram.Message, data map[string]string) {
+	if msg.Caption != "" {
+		data["message.caption"] = msg.Caption
+	}
+	if len(msg.Photo) > 0 {
+		// the last photo size is the largest one
+		photo := msg.Photo[len(msg.Photo)-1]
+		data["message.photo.file_id"] = photo.FileID
+		data["message.photo.width"] = strconv.Itoa(photo.Width)
+		data["message.photo.height"] = strconv.Itoa(photo.Height)
+		data["message.file_id"] = photo.FileID
+	}
+	if doc := msg.Document; doc != nil {
+		data["message.document.file_id"] = doc.FileID
+		data["message.document.file_name"] = doc.FileName
+		data["message.document.mime_type"] = doc.MimeType
+		data["message.document.file_size"] = strconv.Itoa(doc.FileSize)
+		data["message.file_id"] = doc.FileID
+	}
+	if voice := msg.Voice; voice != nil {
+		data["message.voice.file_id"] = voice.FileID
+		data["message.voice.duration"] = strconv.Itoa(voice.Duration)
+		data["message.voice.mime_type"] = voice.MimeType
+		data["message.file_id"] = voice.FileID
+	}
+	if video := msg.Video; video != nil {
+		data["message.video.file_id"] = video.FileID
+		data["message.video.file_name"] = video.FileName
+		data["message.video.duration"] = strconv.Itoa(video.Duration)
+		data["message.video.mime_type"] = video.MimeType
+		data["message.file_id"] = video.FileID
+	}
+	if loc := msg.Location; loc != nil {
+		data["message.location.latitude"] = strconv.FormatFloat(loc.Latitude, 'f', -1, 64)
+		data["message.location.longitude"] = strconv.FormatFloat(loc.Longitude, 'f', -1, 64)
+	}
+	if contact := msg.Contact; contact != nil {
+		data["message.contact.phone_number"] = contact.PhoneNumber
+		data["message.contact.first_name"] = contact.FirstName
+		data["message.contact.last_name"] = contact.LastName
+		data["message.contact.user_id"] = strconv.FormatInt(contact.UserID, 10)
+	}
+}
+
 func (i *Interpolator) Interpolate(text string) string {
 	res := os.Expand(text, i.expander())
 	fmt.Printf("Interpolated: %q -> %q\n", text, res)
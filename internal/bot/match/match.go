@@ -0,0 +1,89 @@
+// Package match keeps values captured by event filters while checking
+// an update, e.g. named groups of regular expressions.
+package match
+
+import (
+	"context"
+	"sync"
+)
+
+const (
+	// Groups is a namespace for regular expression named groups.
+	Groups = "match"
+	// Args is a namespace for named command arguments.
+	Args = "message.args"
+	// Callback is a namespace for callback data parameters.
+	Callback = "callback"
+)
+
+// Values captured by filters grouped by namespace.
+type Values map[string]map[string]string
+
+// Get returns captured values of namespace.
+func (v Values) Get(ns string) map[string]string {
+	return v[ns]
+}
+
+type holder struct {
+	vals Values
+	mx   sync.Mutex
+}
+
+type ctxKey struct{}
+
+// NewContext creates new context with empty values holder.
+func NewContext(ctx context.Context) context.Context {
+	return context.WithValue(ctx, ctxKey{}, &holder{vals: make(Values)})
+}
+
+// Set captured values for namespace. It does nothing if context
+// was not created with NewContext.
+func Set(ctx context.Context, ns string, vals map[string]string) {
+	h, ok := ctx.Value(ctxKey{}).(*holder)
+	if !ok {
+		return
+	}
+	h.mx.Lock()
+	defer h.mx.Unlock()
+
+	m, ok := h.vals[ns]
+	if !ok {
+		m = make(map[string]string, len(vals))
+		h.vals[ns] = m
+	}
+	for k, v := range vals {
+		m[k] = v
+	}
+}
+
+// FromCtx returns a copy of captured values from context.
+func FromCtx(ctx context.Context) Values {
+	h, ok := ctx.Value(ctxKey{}).(*holder)
+	if !ok {
+		return Values{}
+	}
+	h.mx.Lock()
+	defer h.mx.Unlock()
+
+	res := make(Values, len(h.vals))
+	for ns, m := range h.vals {
+		cpy := make(map[string]string, len(m))
+		for k, v := range m {
+			cpy[k] = v
+		}
+		res[ns] = cpy
+	}
+	return res
+}
+
+// Regexp returns named groups of regular expression submatches.
+func Regexp(names []string, submatches []string) map[string]string {
+	res := make(map[string]string, len(names))
+	for i, name := range names {
+		if name == "" || i >= len(submatches) {
+			continue
+		}
+		res[name] = submatches[i]
+	}
+	return res
+}
@@ -0,0 +1,22 @@
+package match
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValues(t *testing.T) {
+	re := regexp.MustCompile(`^remind me in (?P<amount>\d+) (?P<unit>\w+)$`)
+	ctx := NewContext(context.Background())
+	Set(ctx, Groups, Regexp(re.SubexpNames(), re.FindStringSubmatch("remind me in 10 minutes")))
+	require.Equal(t, map[string]string{"amount": "10", "unit": "minutes"}, FromCtx(ctx).Get(Groups))
+}
+
+func TestNoHolder(t *testing.T) {
+	ctx := context.Background()
+	Set(ctx, Groups, map[string]string{"foo": "bar"})
+	require.Empty(t, FromCtx(ctx))
+}
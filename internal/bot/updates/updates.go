@@ -0,0 +1,278 @@
+// Package updates receives telegram updates including update types
+// which are not supported by telegram API library.
+package updates
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"time"
+
+	"github.com/g4s8/openbots/pkg/types"
+	telegram "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+// Update is a telegram update with message reaction field.
+type Update struct {
+	telegram.Update
+	MessageReaction *MessageReaction `json:"message_reaction"`
+	// ThreadID is a forum topic ID of the update message, it's zero
+	// if the message is not in the forum topic.
+	ThreadID int `json:"-"`
+	// Shared is users or chat shared by the message with request
+	// keyboard button, it's nil for other messages.
+	Shared *Shared `json:"-"`
+}
+
+// Shared is users or chat shared by user with request keyboard button.
+type Shared struct {
+	// RequestID is an ID of the request button.
+	RequestID int
+	UserIDs   []int64
+	ChatID    int64
+}
+
+// sharedMessage is a message fields of shared users and chats.
+type sharedMessage struct {
+	UsersShared *struct {
+		RequestID int     `json:"request_id"`
+		UserIDs   []int64 `json:"user_ids"`
+		Users     []struct {
+			UserID int64 `json:"user_id"`
+		} `json:"users"`
+	} `json:"users_shared"`
+	ChatShared *struct {
+		RequestID int   `json:"request_id"`
+		ChatID    int64 `json:"chat_id"`
+	} `json:"chat_shared"`
+}
+
+func (m *sharedMessage) shared() *Shared {
+	if us := m.UsersShared; us != nil {
+		res := &Shared{RequestID: us.RequestID, UserIDs: us.UserIDs}
+		for _, u := range us.Users {
+			if !slices.Contains(res.UserIDs, u.UserID) {
+				res.UserIDs = append(res.UserIDs, u.UserID)
+			}
+		}
+		return res
+	}
+	if cs := m.ChatShared; cs != nil {
+		return &Shared{RequestID: cs.RequestID, ChatID: cs.ChatID}
+	}
+	return nil
+}
+
+// topicMessage is a message fields of forum topics.
+type topicMessage struct {
+	MessageThreadID int  `json:"message_thread_id"`
+	IsTopicMessage  bool `json:"is_topic_message"`
+}
+
+func (u *Update) UnmarshalJSON(data []byte) error {
+	type plain Update
+	if err := json.Unmarshal(data, (*plain)(u)); err != nil {
+		return err
+	}
+	var topics struct {
+		Message       *topicMessage `json:"message"`
+		EditedMessage *topicMessage `json:"edited_message"`
+		CallbackQuery *struct {
+			Message *topicMessage `json:"message"`
+		} `json:"callback_query"`
+	}
+	if err := json.Unmarshal(data, &topics); err != nil {
+		return err
+	}
+	msg := topics.Message
+	if msg == nil {
+		msg = topics.EditedMessage
+	}
+	if msg == nil && topics.CallbackQuery != nil {
+		msg = topics.CallbackQuery.Message
+	}
+	if msg != nil && msg.IsTopicMessage {
+		u.ThreadID = msg.MessageThreadID
+	}
+	if u.Message != nil {
+		var shared struct {
+			Message sharedMessage `json:"message"`
+		}
+		if err := json.Unmarshal(data, &shared); err != nil {
+			return err
+		}
+		u.Shared = shared.Message.shared()
+	}
+	return nil
+}
+
+// MessageReaction is a change of a reaction on a message by a user.
+type MessageReaction struct {
+	Chat      telegram.Chat  `json:"chat"`
+	MessageID int            `json:"message_id"`
+	User      *telegram.User `json:"user"`
+	ActorChat *telegram.Chat `json:"actor_chat"`
+	Date      int            `json:"date"`
+	Old       []ReactionType `json:"old_reaction"`
+	New       []ReactionType `json:"new_reaction"`
+}
+
+// ReactionType is emoji or custom emoji reaction.
+type ReactionType struct {
+	Type          string `json:"type"`
+	Emoji         string `json:"emoji"`
+	CustomEmojiID string `json:"custom_emoji_id"`
+}
+
+// String returns emoji of the reaction or custom emoji ID.
+func (r ReactionType) String() string {
+	if r.Type == "custom_emoji" {
+		return r.CustomEmojiID
+	}
+	return r.Emoji
+}
+
+// TelegramUpdate returns telegram update for handlers. Message reaction
+// is converted to a synthetic message update with reacted message ID,
+// chat and user, the reaction itself is passed via context of the update.
+func (u *Update) TelegramUpdate() *telegram.Update {
+	if r := u.MessageReaction; r != nil {
+		chat := r.Chat
+		return &telegram.Update{
+			UpdateID: u.UpdateID,
+			Message: &telegram.Message{
+				MessageID:  r.MessageID,
+				From:       r.User,
+				SenderChat: r.ActorChat,
+				Chat:       &chat,
+				Date:       r.Date,
+			},
+		}
+	}
+	upd := u.Update
+	return &upd
+}
+
+// Context returns context with fields of the update which are not supported
+// by telegram library, handlers and filters get them from the context.
+func Context(ctx context.Context, u *Update) context.Context {
+	ctx = ContextWithReaction(ctx, u.MessageReaction)
+	ctx = ContextWithThread(ctx, u.ThreadID)
+	return ContextWithShared(ctx, u.Shared)
+}
+
+type reactionKey struct{}
+
+// ContextWithReaction returns context with message reaction.
+func ContextWithReaction(ctx context.Context, r *MessageReaction) context.Context {
+	if r == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, reactionKey{}, r)
+}
+
+// ReactionFromCtx returns message reaction of the update or nil.
+func ReactionFromCtx(ctx context.Context) *MessageReaction {
+	r, _ := ctx.Value(reactionKey{}).(*MessageReaction)
+	return r
+}
+
+type threadKey struct{}
+
+// ContextWithThread returns context with forum topic ID of the update.
+func ContextWithThread(ctx context.Context, id int) context.Context {
+	if id == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, threadKey{}, id)
+}
+
+// ThreadFromCtx returns forum topic ID of the update or zero.
+func ThreadFromCtx(ctx context.Context) int {
+	id, _ := ctx.Value(threadKey{}).(int)
+	return id
+}
+
+type sharedKey struct{}
+
+// ContextWithShared returns context with users or chat shared by the update message.
+func ContextWithShared(ctx context.Context, s *Shared) context.Context {
+	if s == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, sharedKey{}, s)
+}
+
+// SharedFromCtx returns users or chat shared by the update message or nil.
+func SharedFromCtx(ctx context.Context) *Shared {
+	s, _ := ctx.Value(sharedKey{}).(*Shared)
+	return s
+}
+
+type pollKey struct{}
+
+// ContextWithPoll returns context with the poll of poll answer update.
+func ContextWithPoll(ctx context.Context, poll *types.Poll) context.Context {
+	if poll == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, pollKey{}, poll)
+}
+
+// PollFromCtx returns the poll of poll answer update or nil.
+func PollFromCtx(ctx context.Context) *types.Poll {
+	p, _ := ctx.Value(pollKey{}).(*types.Poll)
+	return p
+}
+
+// Poll receives updates using long polling until context is canceled.
+func Poll(ctx context.Context, api *telegram.BotAPI, cfg telegram.UpdateConfig, log zerolog.Logger) <-chan Update {
+	ch := make(chan Update, api.Buffer)
+	go func() {
+		defer close(ch)
+		for ctx.Err() == nil {
+			updates, err := getUpdates(api, cfg)
+			if err != nil {
+				log.Error().Err(err).Msg("Failed to get updates, retrying in 3 seconds")
+				select {
+				case <-ctx.Done():
+				case <-time.After(3 * time.Second):
+				}
+				continue
+			}
+			for _, upd := range updates {
+				if upd.UpdateID < cfg.Offset {
+					continue
+				}
+				cfg.Offset = upd.UpdateID + 1
+				select {
+				case ch <- upd:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return ch
+}
+
+func getUpdates(api *telegram.BotAPI, cfg telegram.UpdateConfig) ([]Update, error) {
+	params := make(telegram.Params)
+	params.AddNonZero("offset", cfg.Offset)
+	params.AddNonZero("limit", cfg.Limit)
+	params.AddNonZero("timeout", cfg.Timeout)
+	if err := params.AddInterface("allowed_updates", cfg.AllowedUpdates); err != nil {
+		return nil, errors.Wrap(err, "add allowed updates")
+	}
+	resp, err := api.MakeRequest("getUpdates", params)
+	if err != nil {
+		return nil, errors.Wrap(err, "get updates")
+	}
+	var updates []Update
+	if err := json.Unmarshal(resp.Result, &updates); err != nil {
+		return nil, errors.Wrap(err, "decode updates")
+	}
+	return updates, nil
+}
@@ -11,4 +11,7 @@ type Config struct {
 
 	// RequestTimeout - HTTP request timeout.
 	RequestTimeout time.Duration
+
+	// WebApp - Mini App endpoints configuration, optional.
+	WebApp *WebAppConfig
 }
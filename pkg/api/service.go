@@ -80,6 +80,15 @@ func (s *Service) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 		jsonPayload.Payload = jsonPayload.Data
 	}
 
+	payload := Request{
+		ChatID:  types.ChatID(jsonPayload.ChatID),
+		Payload: jsonPayload.Payload,
+	}
+	s.call(ctx, w, logger, handler, payload)
+}
+
+// call handler with request and write response.
+func (s *Service) call(ctx context.Context, w http.ResponseWriter, logger zerolog.Logger, handler Handler, payload Request) {
 	select {
 	case <-ctx.Done():
 		http.Error(w, "request timeout", http.StatusRequestTimeout)
@@ -87,10 +96,6 @@ func (s *Service) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	default:
 	}
 
-	payload := Request{
-		ChatID:  types.ChatID(jsonPayload.ChatID),
-		Payload: jsonPayload.Payload,
-	}
 	err := handler.Call(ctx, payload)
 	if err == nil {
 		s.logger.Info().Interface("payload", payload.Payload).Int("chat_id", int(payload.ChatID)).Msg("Handler called")
@@ -126,6 +131,13 @@ func (s *Service) Start(ctx context.Context) error {
 	mux := http.NewServeMux()
 	mux.Handle("/handlers/", s)
 	mux.Handle("/health", &health{}) // TODO: impl
+	if s.cfg.WebApp != nil {
+		mux.Handle("/webapp/", &webApp{
+			svc:    s,
+			cfg:    *s.cfg.WebApp,
+			logger: s.logger.With().Str("component", "webapp").Logger(),
+		})
+	}
 	s.srv.Handler = mux
 	ln, err := net.Listen("tcp", s.srv.Addr)
 	if err != nil {
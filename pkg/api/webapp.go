@@ -0,0 +1,214 @@
+package api
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/g4s8/openbots/pkg/types"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+// WebAppConfig configures Mini App endpoints of API service.
+type WebAppConfig struct {
+	// Token of the bot to validate init data.
+	Token string
+	// Assets provider to serve static Mini App files, optional.
+	Assets types.Assets
+	// Root is an assets key prefix of Mini App files.
+	Root string
+	// MaxAge of init data, default is DefaultWebAppMaxAge.
+	MaxAge time.Duration
+	// Handlers are IDs of API handlers which could be called by Mini Apps,
+	// other API handlers are not available for Mini Apps.
+	Handlers []string
+}
+
+// DefaultWebAppMaxAge is a default max age of Mini App init data.
+const DefaultWebAppMaxAge = 24 * time.Hour
+
+var (
+	// ErrInitDataInvalid is returned when init data signature is invalid.
+	ErrInitDataInvalid = errors.New("invalid init data signature")
+	// ErrInitDataExpired is returned when init data is older than max age.
+	ErrInitDataExpired = errors.New("init data expired")
+)
+
+// ValidateInitData validates Mini App init data signature with bot token,
+// and returns init data values. Auth date is checked if maxAge is not zero.
+func ValidateInitData(initData, token string, maxAge time.Duration) (url.Values, error) {
+	vals, err := url.ParseQuery(initData)
+	if err != nil {
+		return nil, errors.Wrap(err, "parse init data")
+	}
+	hash := vals.Get("hash")
+	if hash == "" {
+		return nil, ErrInitDataInvalid
+	}
+	pairs := make([]string, 0, len(vals))
+	for k := range vals {
+		if k == "hash" {
+			continue
+		}
+		pairs = append(pairs, k+"="+vals.Get(k))
+	}
+	sort.Strings(pairs)
+
+	secret := hmac.New(sha256.New, []byte("WebAppData"))
+	secret.Write([]byte(token))
+	sig := hmac.New(sha256.New, secret.Sum(nil))
+	sig.Write([]byte(strings.Join(pairs, "\n")))
+	expected := hex.EncodeToString(sig.Sum(nil))
+	if !hmac.Equal([]byte(expected), []byte(hash)) {
+		return nil, ErrInitDataInvalid
+	}
+
+	if maxAge > 0 {
+		authDate, err := strconv.ParseInt(vals.Get("auth_date"), 10, 64)
+		if err != nil {
+			return nil, errors.Wrap(err, "parse auth date")
+		}
+		if time.Since(time.Unix(authDate, 0)) > maxAge {
+			return nil, ErrInitDataExpired
+		}
+	}
+	return vals, nil
+}
+
+// WebAppUser is a user of Mini App init data.
+type WebAppUser struct {
+	ID           int64  `json:"id"`
+	FirstName    string `json:"first_name"`
+	LastName     string `json:"last_name"`
+	Username     string `json:"username"`
+	LanguageCode string `json:"language_code"`
+}
+
+// InitDataUser decodes user of validated init data.
+func InitDataUser(vals url.Values) (*WebAppUser, error) {
+	src := vals.Get("user")
+	if src == "" {
+		return nil, errors.New("init data has no user")
+	}
+	var user WebAppUser
+	if err := json.Unmarshal([]byte(src), &user); err != nil {
+		return nil, errors.Wrap(err, "decode init data user")
+	}
+	return &user, nil
+}
+
+var reWebAppPath = regexp.MustCompile(`^/webapp/handlers/([a-zA-Z0-9-]+)$`)
+
+// webApp serves Mini App endpoints: calls API handlers with validated init data
+// and serves static files.
+type webApp struct {
+	svc    *Service
+	cfg    WebAppConfig
+	logger zerolog.Logger
+}
+
+func (h *webApp) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Body != nil {
+		defer req.Body.Close()
+	}
+	switch req.Method {
+	case http.MethodPost:
+		h.call(w, req)
+	case http.MethodGet, http.MethodHead:
+		h.serveFile(w, req)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// call handler for the user of init data, the user ID is used as a chat ID.
+func (h *webApp) call(w http.ResponseWriter, req *http.Request) {
+	matches := reWebAppPath.FindStringSubmatch(req.URL.Path)
+	if matches == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	name := matches[1]
+	handler, ok := h.svc.handlers[name]
+	if !ok || !slices.Contains(h.cfg.Handlers, name) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	logger := h.logger.With().Str("path", req.URL.Path).Str("handler", name).Logger()
+
+	var payload struct {
+		InitData string            `json:"initData"`
+		Params   map[string]string `json:"params"`
+	}
+	if err := json.NewDecoder(req.Body).Decode(&payload); err != nil {
+		logger.Err(err).Msg("Invalid payload")
+		http.Error(w, fmt.Sprintf("invalid payload: %v", err), http.StatusBadRequest)
+		return
+	}
+	maxAge := h.cfg.MaxAge
+	if maxAge == 0 {
+		maxAge = DefaultWebAppMaxAge
+	}
+	vals, err := ValidateInitData(payload.InitData, h.cfg.Token, maxAge)
+	if err != nil {
+		logger.Info().Err(err).Msg("Init data validation failed")
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	user, err := InitDataUser(vals)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), h.svc.cfg.RequestTimeout)
+	defer cancel()
+	h.svc.call(ctx, w, logger, handler, Request{
+		ChatID:  types.ChatID(user.ID),
+		Payload: payload.Params,
+	})
+}
+
+// serveFile serves Mini App file from assets, `index.html` is served for root path.
+func (h *webApp) serveFile(w http.ResponseWriter, req *http.Request) {
+	if h.cfg.Assets == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	name := strings.TrimPrefix(path.Clean(req.URL.Path), "/webapp")
+	name = strings.TrimPrefix(name, "/")
+	if name == "" {
+		name = "index.html"
+	}
+	f, err := h.cfg.Assets.LoadAsset(req.Context(), path.Join(h.cfg.Root, name))
+	if err != nil {
+		h.logger.Debug().Err(err).Str("file", name).Msg("Mini App file not found")
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	defer f.Close()
+	if ct := mime.TypeByExtension(path.Ext(name)); ct != "" {
+		w.Header().Set("Content-Type", ct)
+	}
+	if req.Method == http.MethodHead {
+		return
+	}
+	if _, err := io.Copy(w, f); err != nil {
+		h.logger.Err(err).Str("file", name).Msg("Failed to serve Mini App file")
+	}
+}
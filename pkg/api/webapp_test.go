@@ -0,0 +1,113 @@
+package api
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+const testToken = "123456:ABC-DEF"
+
+// signInitData signs init data as Telegram does.
+func signInitData(vals url.Values, dataCheck string) string {
+	secret := hmac.New(sha256.New, []byte("WebAppData"))
+	secret.Write([]byte(testToken))
+	sig := hmac.New(sha256.New, secret.Sum(nil))
+	sig.Write([]byte(dataCheck))
+	vals.Set("hash", hex.EncodeToString(sig.Sum(nil)))
+	return vals.Encode()
+}
+
+func TestValidateInitData(t *testing.T) {
+	authDate := strconv.FormatInt(time.Now().Add(-time.Minute).Unix(), 10)
+	user := `{"id":42,"first_name":"John","username":"john"}`
+	vals := url.Values{
+		"auth_date": {authDate},
+		"query_id":  {"AAH"},
+		"user":      {user},
+	}
+	initData := signInitData(vals, "auth_date="+authDate+"\nquery_id=AAH\nuser="+user)
+
+	res, err := ValidateInitData(initData, testToken, time.Hour)
+	require.NoError(t, err)
+	u, err := InitDataUser(res)
+	require.NoError(t, err)
+	require.Equal(t, int64(42), u.ID)
+	require.Equal(t, "john", u.Username)
+
+	_, err = ValidateInitData(initData, "654321:XYZ", time.Hour)
+	require.ErrorIs(t, err, ErrInitDataInvalid)
+
+	_, err = ValidateInitData(initData, testToken, time.Second)
+	require.ErrorIs(t, err, ErrInitDataExpired)
+
+	tampered, err := url.ParseQuery(initData)
+	require.NoError(t, err)
+	tampered.Set("user", `{"id":1}`)
+	_, err = ValidateInitData(tampered.Encode(), testToken, 0)
+	require.ErrorIs(t, err, ErrInitDataInvalid)
+
+	_, err = ValidateInitData("auth_date="+authDate, testToken, 0)
+	require.ErrorIs(t, err, ErrInitDataInvalid)
+}
+
+type handlerFunc func(context.Context, Request) error
+
+func (f handlerFunc) Call(ctx context.Context, req Request) error {
+	return f(ctx, req)
+}
+
+func TestWebAppCall(t *testing.T) {
+	var calls []Request
+	handler := handlerFunc(func(_ context.Context, req Request) error {
+		calls = append(calls, req)
+		return nil
+	})
+	svc := NewService(Config{RequestTimeout: time.Second}, map[string]Handler{
+		"order": handler, "admin": handler,
+	})
+	app := &webApp{
+		svc:    svc,
+		cfg:    WebAppConfig{Token: testToken, Handlers: []string{"order"}},
+		logger: zerolog.Nop(),
+	}
+	initData := func(authDate time.Time) string {
+		date := strconv.FormatInt(authDate.Unix(), 10)
+		user := `{"id":42}`
+		return signInitData(url.Values{"auth_date": {date}, "user": {user}},
+			"auth_date="+date+"\nuser="+user)
+	}
+	call := func(name, initData string) int {
+		body, err := json.Marshal(map[string]any{
+			"initData": initData,
+			"params":   map[string]string{"product": "apple"},
+		})
+		require.NoError(t, err)
+		req := httptest.NewRequest(http.MethodPost, "/webapp/handlers/"+name, strings.NewReader(string(body)))
+		rec := httptest.NewRecorder()
+		app.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	require.Equal(t, http.StatusNoContent, call("order", initData(time.Now())))
+	require.Len(t, calls, 1)
+	require.Equal(t, int64(42), calls[0].ChatID.Int64())
+	require.Equal(t, "apple", calls[0].Payload["product"])
+
+	require.Equal(t, http.StatusNotFound, call("admin", initData(time.Now())), "handler is not opt-in")
+	require.Equal(t, http.StatusUnauthorized, call("order", initData(time.Now().Add(-25*time.Hour))),
+		"init data is older than default max age")
+	require.Len(t, calls, 1)
+}
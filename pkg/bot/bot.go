@@ -19,16 +19,21 @@ import (
 	"github.com/g4s8/openbots/internal/bot/filters"
 	"github.com/g4s8/openbots/internal/bot/handlers"
 	"github.com/g4s8/openbots/internal/bot/logger"
+	"github.com/g4s8/openbots/internal/bot/match"
+	"github.com/g4s8/openbots/internal/bot/updates"
 	"github.com/g4s8/openbots/pkg/api"
 	"github.com/g4s8/openbots/pkg/assets"
 	ctx "github.com/g4s8/openbots/pkg/context"
+	"github.com/g4s8/openbots/pkg/jobs"
 	logwrap "github.com/g4s8/openbots/pkg/log"
 	"github.com/g4s8/openbots/pkg/payments"
+	"github.com/g4s8/openbots/pkg/polls"
 	"github.com/g4s8/openbots/pkg/secrets"
 	"github.com/g4s8/openbots/pkg/spec"
 	"github.com/g4s8/openbots/pkg/state"
 	"github.com/g4s8/openbots/pkg/types"
 	"github.com/pkg/errors"
+	"github.com/robfig/cron/v3"
 	"github.com/rs/zerolog"
 
 	telegram "github.com/go-telegram-bot-api/telegram-bot-api/v5"
@@ -36,32 +41,92 @@ import (
 
 var _ types.Bot = (*Bot)(nil)
 
+// allowedUpdates is a list of update types the bot receives, chat_member
+// updates are not sent by Telegram unless specified explicitly.
+var allowedUpdates = []string{
+	"message", "edited_message", "channel_post", "edited_channel_post",
+	"inline_query", "chosen_inline_result", "callback_query",
+	"shipping_query", "pre_checkout_query", "poll", "poll_answer",
+	"my_chat_member", "chat_member", "chat_join_request", "message_reaction",
+}
+
 type eventHandler struct {
 	types.EventFilter
 	types.Handler
 	types.DataLoader
+	// guard is checked after data loading.
+	guard    types.EventFilter
+	priority int
+	final    bool
+}
+
+// HandlerOptions are options of event handler.
+type HandlerOptions struct {
+	// Priority of the handler, handlers with higher priority
+	// are executed first. Handlers with the same priority are
+	// executed in registration order.
+	Priority int
+	// Final stops update handling after this handler.
+	Final bool
+	// DataLoader loads handler data, it's optional.
+	DataLoader types.DataLoader
+}
+
+// sortHandlers sorts handlers by priority keeping registration order
+// for the same priority, fallback handler should be the last handler.
+func sortHandlers(hs []*eventHandler) {
+	slices.SortStableFunc(hs, func(left, right *eventHandler) int {
+		leftFallback, rightFallback := left.EventFilter == filters.Fallback, right.EventFilter == filters.Fallback
+		if leftFallback != rightFallback {
+			if leftFallback {
+				return 1
+			}
+			return -1
+		}
+		return right.priority - left.priority
+	})
 }
 
 // Bot is a main bot instance.
 type Bot struct {
-	botAPI   *telegram.BotAPI
-	apiAddr  string
-	cp       *botctx.Provider
-	state    types.StateProvider
-	assets   types.Assets
-	payments types.PaymentProviders
-	secrets  types.Secrets
-	httpCli  *http.Client
-	ucp      *handlers.UpdateContextProvider
-	log      zerolog.Logger
+	botAPI  *telegram.BotAPI
+	apiAddr string
+	webApp  *api.WebAppConfig
+	// adminChat is a default target chat of forward and copy replies.
+	adminChat string
+	// supportChat is an operators chat of support relay, if configured.
+	supportChat types.ChatID
+	cp          *botctx.Provider
+	state       types.StateProvider
+	assets      types.Assets
+	payments    types.PaymentProviders
+	jobs        types.JobsProvider
+	polls       types.PollsProvider
+	secrets     types.Secrets
+	httpCli     *http.Client
+	ucp         *handlers.UpdateContextProvider
+	log         zerolog.Logger
+	// updateTimeout is a maximum time to handle one update.
+	updateTimeout time.Duration
 
 	handlers    []*eventHandler
 	apiHandlers map[string][]api.Handler
 	apiService  *api.Service
-
-	stopOnce sync.Once
-	quitCh   chan struct{}
-	doneCh   chan struct{}
+	cron        *cron.Cron
+	delayed     map[string][]*eventHandler
+	expired     map[string][]*eventHandler
+	// webAppHandlers are IDs of API handlers available for Mini Apps.
+	webAppHandlers []string
+
+	stopOnce   sync.Once
+	stopPoll   context.CancelFunc
+	quitCh     chan struct{}
+	doneCh     chan struct{}
+	background sync.WaitGroup
+	// queue handles updates of different chats concurrently.
+	queue *chatQueue
+	// chats caches chats of scheduled updates by ID.
+	chats sync.Map
 }
 
 // NewWithOptions creates a new bot instance with options or default values for empty options.
@@ -73,6 +138,10 @@ func NewWithOptions(botAPI *telegram.BotAPI, opts ...Option) *Bot {
 		quitCh:      make(chan struct{}, 1),
 		doneCh:      make(chan struct{}, 1),
 		log:         zerolog.Nop(),
+		cron:        cron.New(),
+		delayed:     make(map[string][]*eventHandler),
+		expired:     make(map[string][]*eventHandler),
+		queue:       newChatQueue(),
 	}
 
 	for _, opt := range opts {
@@ -82,6 +151,9 @@ func NewWithOptions(botAPI *telegram.BotAPI, opts ...Option) *Bot {
 	if b.httpCli == nil {
 		b.httpCli = http.DefaultClient
 	}
+	if b.updateTimeout == 0 {
+		b.updateTimeout = spec.DefaultUpdateTimeout
+	}
 	if b.state == nil {
 		b.state = state.NewMemory(nil)
 	}
@@ -97,6 +169,12 @@ func NewWithOptions(botAPI *telegram.BotAPI, opts ...Option) *Bot {
 	if b.secrets == nil {
 		b.secrets = secrets.Stub
 	}
+	if b.jobs == nil {
+		b.jobs = jobs.NewMemory()
+	}
+	if b.polls == nil {
+		b.polls = polls.NewMemory()
+	}
 	b.ucp = handlers.NewUpdateContextProvider(b.secrets, b.state)
 
 	return b
@@ -134,11 +212,16 @@ func NewFromSpec(s *spec.Bot) (*Bot, error) {
 	} else {
 		log = log.Level(zerolog.InfoLevel)
 	}
+	for _, w := range s.Warnings() {
+		log.Warn().Msg(w)
+	}
 
 	var (
 		sp types.StateProvider
 		cp types.ContextProvider
 		ap types.Assets
+		jp types.JobsProvider
+		pp types.PollsProvider
 	)
 
 	if s.Config == nil {
@@ -173,12 +256,45 @@ func NewFromSpec(s *spec.Bot) (*Bot, error) {
 		}
 		log.Debug().Msg("Database connected")
 		sp = state.NewDB(db, botID)
+		if err := ctx.InitDB(context.Background(), db); err != nil {
+			return nil, errors.Wrap(err, "init context storage")
+		}
 		cp = ctx.NewDBProvider(db, botID)
+		jdb := jobs.NewDB(db, botID)
+		if err := jdb.Init(context.Background()); err != nil {
+			return nil, errors.Wrap(err, "init jobs storage")
+		}
+		jp = jdb
+		pdb := polls.NewDB(db, botID)
+		if err := pdb.Init(context.Background()); err != nil {
+			return nil, errors.Wrap(err, "init polls storage")
+		}
+		pp = pdb
 	}
 
 	var apiAddr string
+	var webApp *api.WebAppConfig
 	if s.Config.Api != nil {
 		apiAddr = s.Config.Api.Address
+		if wa := s.Config.Api.WebApp; wa != nil {
+			webApp = &api.WebAppConfig{Root: wa.Assets}
+			if wa.MaxAge != "" {
+				maxAge, err := time.ParseDuration(wa.MaxAge)
+				if err != nil {
+					return nil, errors.Wrap(err, "parse web app max age")
+				}
+				webApp.MaxAge = maxAge
+			}
+		}
+	}
+
+	updateTimeout := spec.DefaultUpdateTimeout
+	if s.Config.UpdateTimeout != "" {
+		d, err := time.ParseDuration(s.Config.UpdateTimeout)
+		if err != nil {
+			return nil, errors.Wrap(err, "parse update timeout")
+		}
+		updateTimeout = d
 	}
 
 	if s.Config.Assets.Provider == "fs" {
@@ -209,7 +325,23 @@ func NewFromSpec(s *spec.Bot) (*Bot, error) {
 	sp = logwrap.WrapStateProvider(sp, log)
 	cp = logwrap.WrapContextProvider(cp, log)
 
-	bot := New(botAPI, sp, cp, ap, paymentProviders, secrets.Stub, apiAddr, log)
+	opts := []Option{
+		WithStateProvider(sp),
+		WithContextProvider(cp),
+		WithAssets(ap),
+		WithPaymentProviders(paymentProviders),
+		WithJobsProvider(jp),
+		WithPollsProvider(pp),
+		WithSecrets(secrets.Stub),
+		WithAPIAddr(apiAddr),
+		WithLogger(log),
+		WithAdminChat(s.Config.AdminChat),
+		WithUpdateTimeout(updateTimeout),
+	}
+	if webApp != nil {
+		opts = append(opts, WithWebApp(*webApp))
+	}
+	bot := NewWithOptions(botAPI, opts...)
 
 	if err := bot.SetupHandlersFromSpec(s.Handlers); err != nil {
 		return nil, errors.Wrap(err, "setup handlers")
@@ -221,6 +353,25 @@ func NewFromSpec(s *spec.Bot) (*Bot, error) {
 		}
 	}
 
+	if s.Support != nil {
+		if err := bot.SetupSupport(s.Support); err != nil {
+			return nil, errors.Wrap(err, "setup support")
+		}
+	}
+
+	// commands and profile are synced again on next start,
+	// so the bot could start without them, e.g. on rate limit errors
+	if s.Commands != nil {
+		if err := bot.SyncCommands(s.Commands); err != nil {
+			log.Warn().Err(err).Msg("Failed to sync commands menu")
+		}
+	}
+	if s.Profile != nil {
+		if err := bot.SyncProfile(s.Profile); err != nil {
+			log.Warn().Err(err).Msg("Failed to sync bot profile")
+		}
+	}
+
 	return bot, nil
 }
 
@@ -233,34 +384,25 @@ func (b *Bot) SetupHandlersFromSpec(src []*spec.Handler) error {
 			err    error
 		)
 
-		if h.Trigger.Message != nil {
-			filter, err = handlers.NewMessageFilterFromSpec(h.Trigger.Message)
-			if err != nil {
-				return errors.Wrap(err, "create message event filter")
-			}
-		}
-		if h.Trigger.Callback != nil {
-			filter, err = handlers.NewCallbackFilterFromSpec(h.Trigger.Callback)
-			if err != nil {
-				return errors.Wrap(err, "create callback event filter")
+		filter, err = b.triggerFilter(h.Trigger)
+		if err != nil {
+			return err
+		}
+		// context, state and other conditions without update type match only
+		// user messages and callbacks, e.g. they don't match reactions,
+		// chat member updates and poll answers
+		if !h.Trigger.HasUpdateType() {
+			if filter == nil {
+				filter = filters.UserUpdate
+			} else {
+				filter = filters.Join(filters.UserUpdate, filter)
 			}
 		}
-		if h.Trigger.Context != "" {
-			filter = handlers.NewContextFilter(filter, b.cp, h.Trigger.Context)
-		}
-		// TODO: refactor all filters/triggers similat to handlers
-		if h.Trigger.PreCheckout != nil {
-			filter = adaptors.NewPrecheckoutFilter(h.Trigger.PreCheckout)
-		}
-		if h.Trigger.PostCheckout != nil {
-			filter = adaptors.NewPostcheckoutFilter(h.Trigger.PostCheckout)
-		}
-		if len(h.Trigger.State) > 0 {
-			f := adaptors.NewStateFilter(b.state, b.log, h.Trigger.State)
-			filter = filters.Join(filter, f)
-		}
-		if filter == nil && h.Trigger.Fallback {
-			filter = filters.Fallback
+		// top-level conditions may refer handler data,
+		// so they are checked after data loading
+		var guard types.EventFilter
+		if len(h.Trigger.If) > 0 {
+			guard = adaptors.NewConditionFilter(h.Trigger.If)
 		}
 
 		// validator should be the first handler
@@ -272,7 +414,7 @@ func (b *Bot) SetupHandlersFromSpec(src []*spec.Handler) error {
 			hs = append(hs, h)
 		}
 		if h.Replies != nil {
-			h, err := adaptors.Replies(b.botAPI, b.state, b.secrets, b.assets, b.payments, h.Replies, b.log)
+			h, err := adaptors.Replies(b.botAPI, b.state, b.secrets, b.assets, b.payments, b.jobs, b.polls, b.adminChat, h.Replies, b.log)
 			if err != nil {
 				return errors.Wrap(err, "create replies handler")
 			}
@@ -283,7 +425,11 @@ func (b *Bot) SetupHandlersFromSpec(src []*spec.Handler) error {
 		}
 		if h.Context != nil {
 			if h.Context.Set != "" {
-				hs = append(hs, handlers.NewContextSetter(b.cp, h.Context.Set, b.log))
+				setter, err := b.contextSetter(h.Context)
+				if err != nil {
+					return errors.Wrap(err, "create context setter")
+				}
+				hs = append(hs, setter)
 			}
 			if h.Context.Delete != "" {
 				hs = append(hs, handlers.NewContextDeleter(b.cp, h.Context.Delete, b.log))
@@ -299,6 +445,9 @@ func (b *Bot) SetupHandlersFromSpec(src []*spec.Handler) error {
 			}
 			dl = d
 		}
+		if h.Trigger.WebAppData != nil {
+			dl = data.WebAppLoader{}
+		}
 
 		if filter == nil {
 			return errors.New("no event filter")
@@ -306,13 +455,217 @@ func (b *Bot) SetupHandlersFromSpec(src []*spec.Handler) error {
 		if len(hs) == 0 {
 			return errors.New("no handler")
 		}
-		for _, h := range hs {
-			b.HandleWithData(filter, h, dl)
+		ehs := make([]*eventHandler, len(hs))
+		for i, hh := range hs {
+			ehs[i] = &eventHandler{
+				EventFilter: filter, Handler: hh, DataLoader: dl, guard: guard,
+				// only the last step of the handler stops update handling
+				priority: h.Priority, final: h.Final && i == len(hs)-1,
+			}
+		}
+		if err := b.setupPaginates(h.Replies, dl); err != nil {
+			return errors.Wrap(err, "setup paginate handlers")
+		}
+		if h.Trigger.Schedule != nil {
+			if err := b.schedule(h.Trigger.Schedule, ehs); err != nil {
+				return errors.Wrap(err, "schedule handler")
+			}
+			continue
+		}
+		if h.Trigger.Delayed != "" {
+			b.delayed[h.Trigger.Delayed] = append(b.delayed[h.Trigger.Delayed], ehs...)
+			sortHandlers(b.delayed[h.Trigger.Delayed])
+			continue
+		}
+		if h.Trigger.ContextExpired != "" {
+			b.expired[h.Trigger.ContextExpired] = append(b.expired[h.Trigger.ContextExpired], ehs...)
+			b.cp.EnableExpiration()
+			sortHandlers(b.expired[h.Trigger.ContextExpired])
+			continue
+		}
+		b.handlers = append(b.handlers, ehs...)
+		sortHandlers(b.handlers)
+	}
+	return nil
+}
+
+// setupPaginates adds handlers of previous and next buttons callbacks
+// of paginated lists, they use the same data loader as the list handler.
+func (b *Bot) setupPaginates(replies []*spec.Reply, dl types.DataLoader) error {
+	for _, r := range replies {
+		if r.Paginate == nil {
+			continue
+		}
+		filter, err := handlers.NewCallbackFilterFromSpec(&spec.CallbackTrigger{
+			Prefix: handlers.PaginateCallbackPrefix(r.Paginate.Name),
+		})
+		if err != nil {
+			return errors.Wrap(err, "create paginate callback filter")
 		}
+		b.handlers = append(b.handlers, &eventHandler{
+			EventFilter: filter,
+			Handler:     adaptors.NewPaginate(r.Paginate, b.log),
+			DataLoader:  dl,
+			final:       true,
+		})
+		sortHandlers(b.handlers)
 	}
 	return nil
 }
 
+// SetupSupport adds handlers of support relay: user messages in support context
+// are relayed to operators chat, and operator replies are relayed back to users.
+func (b *Bot) SetupSupport(s *spec.Support) error {
+	cfg, err := adaptors.SupportConfig(s, b.adminChat)
+	if err != nil {
+		return err
+	}
+	b.supportChat = types.ChatID(cfg.Chat)
+	cfg.Serialize = b.queue.Do
+	sup := handlers.NewSupport(cfg, b.cp, b.state, b.log)
+	b.handlers = append(b.handlers,
+		&eventHandler{
+			EventFilter: handlers.NewContextFilter(filters.SupportUser{}, b.cp, s.Context),
+			Handler:     sup.UserHandler(),
+			final:       true,
+		},
+		&eventHandler{
+			EventFilter: filters.NewSupportOperator(cfg.Chat, b.state),
+			Handler:     sup.OperatorHandler(),
+			final:       true,
+		})
+	sortHandlers(b.handlers)
+	return nil
+}
+
+// triggerFilter creates event filter for the trigger, it doesn't include
+// top-level `if` conditions of the trigger.
+func (b *Bot) triggerFilter(t *spec.Trigger) (types.EventFilter, error) {
+	var (
+		filter types.EventFilter
+		err    error
+	)
+	if t.Message != nil {
+		filter, err = handlers.NewMessageFilterFromSpec(t.Message)
+		if err != nil {
+			return nil, errors.Wrap(err, "create message event filter")
+		}
+	}
+	if t.EditedMessage != nil {
+		filter, err = handlers.NewMessageFilterWithSource(t.EditedMessage, handlers.SourceEditedMessage)
+		if err != nil {
+			return nil, errors.Wrap(err, "create edited message event filter")
+		}
+	}
+	if t.ChannelPost != nil {
+		filter, err = handlers.NewMessageFilterWithSource(t.ChannelPost, handlers.SourceChannelPost)
+		if err != nil {
+			return nil, errors.Wrap(err, "create channel post event filter")
+		}
+	}
+	if t.EditedChannelPost != nil {
+		filter, err = handlers.NewMessageFilterWithSource(t.EditedChannelPost, handlers.SourceEditedChannelPost)
+		if err != nil {
+			return nil, errors.Wrap(err, "create edited channel post event filter")
+		}
+	}
+	if t.MessageReaction != nil {
+		filter = filters.NewMessageReaction(t.MessageReaction.Emoji)
+	}
+	if t.Callback != nil {
+		filter, err = handlers.NewCallbackFilterFromSpec(t.Callback)
+		if err != nil {
+			return nil, errors.Wrap(err, "create callback event filter")
+		}
+	}
+	if t.Context != "" {
+		filter = handlers.NewContextFilter(filter, b.cp, t.Context)
+	}
+	// TODO: refactor all filters/triggers similat to handlers
+	if t.PreCheckout != nil {
+		filter = adaptors.NewPrecheckoutFilter(t.PreCheckout)
+	}
+	if t.PostCheckout != nil {
+		filter = adaptors.NewPostcheckoutFilter(t.PostCheckout)
+	}
+	if t.InlineQuery != nil {
+		filter = adaptors.NewInlineQueryFilter(t.InlineQuery)
+	}
+	if t.ChosenInlineResult != nil {
+		filter = adaptors.NewChosenInlineResultFilter(t.ChosenInlineResult)
+	}
+	if t.MyChatMember != nil {
+		filter = adaptors.NewChatMemberFilter(true, t.MyChatMember)
+	}
+	if t.ChatMember != nil {
+		filter = adaptors.NewChatMemberFilter(false, t.ChatMember)
+	}
+	if t.ChatJoinRequest != nil {
+		filter = filters.NewChatJoinRequest()
+	}
+	if t.PollAnswer != nil {
+		filter = adaptors.NewPollAnswerFilter(t.PollAnswer)
+	}
+	if t.WebAppData != nil {
+		filter = filters.NewWebAppData(t.WebAppData.Button)
+	}
+	if len(t.State) > 0 {
+		f := adaptors.NewStateFilter(b.state, b.log, t.State)
+		filter = filters.Join(filter, f)
+	}
+	if len(t.ChatType) > 0 {
+		filter = filters.Join(filter, filters.NewChatType(t.ChatType))
+	}
+	if t.Sender != nil {
+		filter = filters.Join(filter, adaptors.NewSenderFilter(b.botAPI, t.Sender))
+	}
+	if len(t.Any) > 0 {
+		fs, err := b.nestedTriggerFilters(t.Any)
+		if err != nil {
+			return nil, errors.Wrap(err, "create any filter")
+		}
+		filter = filters.Join(filter, filters.Any(fs...))
+	}
+	if len(t.All) > 0 {
+		fs, err := b.nestedTriggerFilters(t.All)
+		if err != nil {
+			return nil, errors.Wrap(err, "create all filter")
+		}
+		filter = filters.Join(filter, fs...)
+	}
+	if t.Not != nil {
+		fs, err := b.nestedTriggerFilters([]*spec.Trigger{t.Not})
+		if err != nil {
+			return nil, errors.Wrap(err, "create not filter")
+		}
+		filter = filters.Join(filter, filters.Not(fs[0]))
+	}
+	if filter == nil && t.Fallback {
+		filter = filters.Fallback
+	}
+	if filter == nil && (t.Schedule != nil || t.Delayed != "" || t.ContextExpired != "") {
+		filter = filters.Pass
+	}
+	return filter, nil
+}
+
+// nestedTriggerFilters creates filters for triggers of combinators,
+// `if` conditions of nested triggers are checked with other filters.
+func (b *Bot) nestedTriggerFilters(ts []*spec.Trigger) ([]types.EventFilter, error) {
+	res := make([]types.EventFilter, len(ts))
+	for i, t := range ts {
+		f, err := b.triggerFilter(t)
+		if err != nil {
+			return nil, err
+		}
+		if len(t.If) > 0 {
+			f = filters.Join(f, adaptors.NewConditionFilter(t.If))
+		}
+		res[i] = f
+	}
+	return res, nil
+}
+
 func (b *Bot) SetupApiHandlersFromSpec(src []*spec.ApiHandler) error {
 	for _, h := range src {
 		for _, act := range h.Actions {
@@ -328,7 +681,11 @@ func (b *Bot) SetupApiHandlersFromSpec(src []*spec.ApiHandler) error {
 
 			if act.Context != nil {
 				if act.Context.Set != "" {
-					hs = append(hs, handlers.NewContextSetter(b.cp, act.Context.Set, b.log))
+					setter, err := b.contextSetter(act.Context)
+					if err != nil {
+						return errors.Wrap(err, "create api context setter")
+					}
+					hs = append(hs, setter)
 				}
 				if act.Context.Delete != "" {
 					hs = append(hs, handlers.NewContextDeleter(b.cp, act.Context.Delete, b.log))
@@ -344,27 +701,28 @@ func (b *Bot) SetupApiHandlersFromSpec(src []*spec.ApiHandler) error {
 			}
 			b.log.Info().Int("handler", len(hs)).Str("id", h.ID).Msg("api handler registered")
 		}
+		if h.WebApp {
+			b.webAppHandlers = append(b.webAppHandlers, h.ID)
+		}
 	}
 	return nil
 }
 
 func (b *Bot) Handle(filter types.EventFilter, h types.Handler) {
-	b.handlers = append(b.handlers, &eventHandler{EventFilter: filter, Handler: h})
-	// fallback handler should be the last handler
-	slices.SortFunc(b.handlers, func(left, right *eventHandler) int {
-		leftFallback, rightFallback := left.EventFilter == filters.Fallback, right.EventFilter == filters.Fallback
-		if leftFallback {
-			return 1
-		}
-		if rightFallback {
-			return -1
-		}
-		return 0
-	})
+	b.HandleWithOptions(filter, h, HandlerOptions{})
 }
 
 func (b *Bot) HandleWithData(filter types.EventFilter, h types.Handler, dl types.DataLoader) {
-	b.handlers = append(b.handlers, &eventHandler{EventFilter: filter, Handler: h, DataLoader: dl})
+	b.HandleWithOptions(filter, h, HandlerOptions{DataLoader: dl})
+}
+
+// HandleWithOptions registers event handler with options.
+func (b *Bot) HandleWithOptions(filter types.EventFilter, h types.Handler, opts HandlerOptions) {
+	b.handlers = append(b.handlers, &eventHandler{
+		EventFilter: filter, Handler: h, DataLoader: opts.DataLoader,
+		priority: opts.Priority, final: opts.Final,
+	})
+	sortHandlers(b.handlers)
 }
 
 func (b *Bot) ApiHandler(id string, h api.Handler) {
@@ -380,17 +738,29 @@ func (b *Bot) Start() error {
 
 	updCfg := telegram.NewUpdate(0)
 	updCfg.Timeout = 30
-	updCh := b.botAPI.GetUpdatesChan(updCfg)
+	updCfg.AllowedUpdates = allowedUpdates
+	pollCtx, stopPoll := context.WithCancel(context.Background())
+	b.stopPoll = stopPoll
+	updCh := updates.Poll(pollCtx, b.botAPI, updCfg, b.log.With().Str("component", "updates").Logger())
 	go func() {
 		defer close(b.doneCh)
 		for {
 			select {
 			case <-b.quitCh:
 				return
-			case upd := <-updCh:
-				ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
-				b.HandleUpdate(ctx, &upd)
-				cancel()
+			case upd, ok := <-updCh:
+				if !ok {
+					return
+				}
+				// updates of the same chat are handled in order, so pauses
+				// and slow handlers of one chat don't block other chats
+				b.queue.Go(handlers.ChatID(upd.TelegramUpdate()), func() {
+					ctx, cancel := context.WithTimeout(context.Background(), b.updateTimeout)
+					defer cancel()
+					if err := b.HandleFullUpdate(ctx, &upd); err != nil {
+						b.log.Error().Err(err).Msg("Handle update")
+					}
+				})
 			}
 		}
 	}()
@@ -399,16 +769,42 @@ func (b *Bot) Start() error {
 			Addr:           b.apiAddr,
 			ReadTimeout:    time.Second * 5,
 			RequestTimeout: time.Second * 3,
+			WebApp:         b.webAppConfig(),
 		})
 		if err := b.apiService.Start(context.TODO()); err != nil {
 			return errors.Wrap(err, "start api service")
 		}
 		b.log.Info().Str("addr", b.apiAddr).Msg("API service started")
 	}
+	b.cron.Start()
+	// delayed jobs are polled only if some handler is triggered by them
+	if len(b.delayed) > 0 {
+		b.background.Add(1)
+		go b.runDelayed()
+	}
+	if b.cp.Expiring() {
+		b.background.Add(1)
+		go b.runContextSweeper()
+	}
 	b.log.Info().Msg("Bot started")
 	return nil
 }
 
+func (b *Bot) webAppConfig() *api.WebAppConfig {
+	if b.webApp == nil {
+		return nil
+	}
+	cfg := *b.webApp
+	if cfg.Token == "" {
+		cfg.Token = b.botAPI.Token
+	}
+	if cfg.Assets == nil {
+		cfg.Assets = b.assets
+	}
+	cfg.Handlers = append(cfg.Handlers, b.webAppHandlers...)
+	return &cfg
+}
+
 func (b *Bot) HandlerAPI(cfg api.Config) *api.Service {
 	handlers := make(map[string]api.Handler, len(b.apiHandlers))
 	for id, hs := range b.apiHandlers {
@@ -420,14 +816,19 @@ func (b *Bot) HandlerAPI(cfg api.Config) *api.Service {
 func (b *Bot) Stop() error {
 	b.stopOnce.Do(func() {
 		b.log.Info().Msg("Stopping bot")
-		b.botAPI.StopReceivingUpdates()
+		if b.stopPoll != nil {
+			b.stopPoll()
+		}
 		close(b.quitCh)
+		<-b.cron.Stop().Done()
+		b.background.Wait()
 		if b.apiService != nil {
 			if err := b.apiService.Stop(context.TODO()); err != nil {
 				b.log.Error().Err(err).Msg("Error stopping API service")
 			}
 		}
 		<-b.doneCh
+		b.queue.Wait()
 		b.log.Info().Msg("Bot stopped")
 	})
 	return nil
@@ -444,10 +845,48 @@ func (b *Bot) HandleUpdate(ctx context.Context, upd *telegram.Update) {
 type handlerWithData struct {
 	handler types.Handler
 	data    types.DataLoader
+	guard   types.EventFilter
+	final   bool
+	// ctx of the handler with values captured by its filter.
+	ctx context.Context
 }
 
+// Update is a telegram update with fields which are not supported by telegram
+// library, e.g. message reactions, forum topics and shared users. It could be
+// decoded from JSON of webhook request.
+type Update = updates.Update
+
 // HandleUpdateErr handles telegram update and returns error if any.
+// Use HandleFullUpdate for updates with reactions, forum topics or shared users.
 func (b *Bot) HandleUpdateErr(ctx context.Context, upd *telegram.Update) error {
+	return b.HandleFullUpdate(ctx, &Update{Update: *upd})
+}
+
+// HandleFullUpdate handles telegram update with fields which are not supported
+// by telegram library and returns error if any.
+func (b *Bot) HandleFullUpdate(ctx context.Context, upd *Update) error {
+	// closed polls can't be answered anymore
+	if p := upd.Poll; p != nil && p.IsClosed {
+		if err := b.polls.Remove(ctx, p.ID); err != nil {
+			return errors.Wrap(err, "remove closed poll")
+		}
+	}
+	if a := upd.PollAnswer; a != nil {
+		poll, err := b.polls.Get(ctx, a.PollID)
+		if err == nil {
+			ctx = updates.ContextWithPoll(ctx, &poll)
+		} else if !errors.Is(err, types.ErrPollNotFound) {
+			return errors.Wrap(err, "get poll")
+		}
+	}
+	return b.handleUpdate(ctx, upd, b.handlers)
+}
+
+// handleUpdate runs matched event handlers for the update,
+// or fallback handler if none of them was matched.
+func (b *Bot) handleUpdate(ctx context.Context, full *updates.Update, src []*eventHandler) error {
+	ctx = updates.Context(ctx, full)
+	upd := full.TelegramUpdate()
 	chatID := handlers.ChatID(upd)
 	log := b.log.With().Str("chat_id", chatID.String()).Logger()
 	log.Debug().Msg("Handling update")
@@ -468,16 +907,17 @@ func (b *Bot) HandleUpdateErr(ctx context.Context, upd *telegram.Update) error {
 	var errs []error
 	hs := make([]handlerWithData, 0)
 	var fallbackHandler handlerWithData
-	for _, h := range b.handlers {
+	for _, h := range src {
 		if h.EventFilter == filters.Fallback {
-			fallbackHandler = handlerWithData{handler: h.Handler, data: h.DataLoader}
+			fallbackHandler = handlerWithData{handler: h.Handler, data: h.DataLoader, ctx: ctx}
 			continue
 		}
-		if check, err := h.Check(ctx, upd); err != nil {
+		hctx := match.NewContext(ctx)
+		if check, err := h.Check(hctx, upd); err != nil {
 			errs = append(errs, errors.Wrap(err, "filter check"))
 			continue
 		} else if check {
-			hs = append(hs, handlerWithData{handler: h.Handler, data: h.DataLoader})
+			hs = append(hs, handlerWithData{handler: h.Handler, data: h.DataLoader, guard: h.guard, final: h.final, ctx: hctx})
 		}
 	}
 
@@ -486,7 +926,7 @@ func (b *Bot) HandleUpdateErr(ctx context.Context, upd *telegram.Update) error {
 	for i, h := range hs {
 		log.Debug().Int("handler", i).Msg("Handling")
 
-		ok, err := runHandler(ctx, b.botAPI, h, upd)
+		ok, err := runHandler(h.ctx, b.botAPI, h, upd)
 		if !handled && ok {
 			handled = true
 		}
@@ -497,11 +937,15 @@ func (b *Bot) HandleUpdateErr(ctx context.Context, upd *telegram.Update) error {
 			}
 			errs = append(errs, err)
 		}
+		if ok && h.final {
+			log.Debug().Int("handler", i).Msg("Final handler, stop handling")
+			break
+		}
 	}
 
-	if !handled && fallbackHandler.handler != nil {
+	if !handled && fallbackHandler.handler != nil && !skipFallback(ctx, upd) {
 		log.Debug().Msg("Handling fallback")
-		ok, err := runHandler(ctx, b.botAPI, fallbackHandler, upd)
+		ok, err := runHandler(fallbackHandler.ctx, b.botAPI, fallbackHandler, upd)
 		if err != nil {
 			if errors.Is(err, handlers.ErrValidationFailed) {
 				log.Info().Err(err).Msg("Validation failed")
@@ -526,9 +970,23 @@ func runHandler(ctx context.Context, botAPI *telegram.BotAPI, h handlerWithData,
 		}
 		ctx = data.ContextWithContainer(ctx, &c)
 	}
+	if h.guard != nil {
+		if ok, err := h.guard.Check(ctx, upd); err != nil {
+			return false, errors.Wrap(err, "check conditions")
+		} else if !ok {
+			return false, nil
+		}
+	}
 
 	if err := h.handler.Handle(ctx, upd, botAPI); err != nil {
 		return true, errors.Wrap(err, "handler")
 	}
 	return true, nil
 }
+
+// skipFallback checks if update is not a user message, such as chat membership
+// update, poll answer or message reaction, such updates are not handled by fallback handler.
+func skipFallback(ctx context.Context, upd *telegram.Update) bool {
+	return upd.MyChatMember != nil || upd.ChatMember != nil || upd.ChatJoinRequest != nil ||
+		upd.PollAnswer != nil || upd.Poll != nil || updates.ReactionFromCtx(ctx) != nil
+}
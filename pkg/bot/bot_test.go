@@ -0,0 +1,148 @@
+package bot
+
+import (
+	"context"
+	"testing"
+
+	"github.com/g4s8/openbots/internal/bot/adaptors"
+	"github.com/g4s8/openbots/pkg/spec"
+	"github.com/g4s8/openbots/pkg/state"
+	"github.com/g4s8/openbots/pkg/types"
+	telegram "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+// anyUpdate is a filter which matches all updates.
+type anyUpdate struct{}
+
+func (anyUpdate) Check(context.Context, *telegram.Update) (bool, error) {
+	return true, nil
+}
+
+// recorder records names of executed handlers.
+type recorder struct {
+	names []string
+}
+
+func (r *recorder) handler(name string) types.Handler {
+	return recordHandler{name: name, r: r}
+}
+
+type recordHandler struct {
+	name string
+	r    *recorder
+}
+
+func (h recordHandler) Handle(context.Context, *telegram.Update, *telegram.BotAPI) error {
+	h.r.names = append(h.r.names, h.name)
+	return nil
+}
+
+// staticData loads constant handler data.
+type staticData map[string]any
+
+func (d staticData) Load(_ context.Context, c *types.DataContainer, _ *telegram.Update) error {
+	c.Set(map[string]any(d))
+	return nil
+}
+
+func testUpdate() *telegram.Update {
+	return &telegram.Update{Message: &telegram.Message{
+		MessageID: 1, Text: "hello",
+		Chat: &telegram.Chat{ID: 42, Type: "private"},
+		From: &telegram.User{ID: 42},
+	}}
+}
+
+func TestHandlersPriority(t *testing.T) {
+	var r recorder
+	b := NewWithOptions(nil)
+	b.Handle(anyUpdate{}, r.handler("first"))
+	b.HandleWithOptions(anyUpdate{}, r.handler("high"), HandlerOptions{Priority: 10})
+	b.Handle(anyUpdate{}, r.handler("second"))
+	b.HandleWithOptions(anyUpdate{}, r.handler("low"), HandlerOptions{Priority: -1})
+	b.HandleWithOptions(anyUpdate{}, r.handler("middle"), HandlerOptions{Priority: 5})
+
+	require.NoError(t, b.HandleUpdateErr(context.Background(), testUpdate()))
+	require.Equal(t, []string{"high", "middle", "first", "second", "low"}, r.names)
+}
+
+func TestHandlersFinal(t *testing.T) {
+	var r recorder
+	b := NewWithOptions(nil)
+	b.Handle(anyUpdate{}, r.handler("default"))
+	b.HandleWithOptions(anyUpdate{}, r.handler("final"), HandlerOptions{Priority: 5, Final: true})
+	b.HandleWithOptions(anyUpdate{}, r.handler("high"), HandlerOptions{Priority: 10})
+
+	require.NoError(t, b.HandleUpdateErr(context.Background(), testUpdate()))
+	require.Equal(t, []string{"high", "final"}, r.names, "handlers after final one are skipped")
+}
+
+func TestHandlersGuard(t *testing.T) {
+	guard := adaptors.NewConditionFilter([]spec.Condition{
+		{Value: "${data.role}", Comparison: spec.Comparison{Eq: "admin"}},
+	})
+	for _, tc := range []struct {
+		role  string
+		names []string
+	}{
+		{role: "admin", names: []string{"admin"}},
+		{role: "user", names: []string{"default"}},
+	} {
+		t.Run(tc.role, func(t *testing.T) {
+			var r recorder
+			b := NewWithOptions(nil)
+			b.Handle(anyUpdate{}, r.handler("default"))
+			b.handlers = append(b.handlers, &eventHandler{
+				EventFilter: anyUpdate{}, Handler: r.handler("admin"),
+				DataLoader: staticData{"role": tc.role}, guard: guard,
+				priority: 10, final: true,
+			})
+			sortHandlers(b.handlers)
+
+			require.NoError(t, b.HandleUpdateErr(context.Background(), testUpdate()))
+			require.Equal(t, tc.names, r.names,
+				"guard checks loaded data, final handler stops only if executed")
+		})
+	}
+}
+
+func TestTriggerCombinators(t *testing.T) {
+	b := NewWithOptions(nil)
+	st := state.NewUserState()
+	st.Set("balance", "50")
+	st.Set("role", "user")
+	require.NoError(t, b.state.Update(context.Background(), 42, st))
+	st.Close()
+	upd := testUpdate()
+	ctx, err := b.ucp.NewContext(context.Background(), upd)
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		trigger string
+		ok      bool
+	}{
+		{trigger: `{message: hello, state: [{key: balance, lt: 100}]}`, ok: true},
+		{trigger: `{message: hello, state: [{key: missing, lt: 100}]}`},
+		{trigger: `{message: hello, any: [{state: [{key: missing, lt: 100}]}, {state: [{key: role, eq: user}]}]}`, ok: true},
+		{trigger: `{message: hello, any: [{state: [{key: missing, lt: 100}]}, {state: [{key: role, eq: admin}]}]}`},
+		{trigger: `{message: hello, all: [{state: [{key: balance, gte: 10}]}, {state: [{key: balance, lte: 50}]}]}`, ok: true},
+		{trigger: `{message: hello, all: [{state: [{key: balance, gte: 10}]}, {state: [{key: balance, lt: 50}]}]}`},
+		{trigger: `{message: hello, not: {state: [{key: role, eq: admin}]}}`, ok: true},
+		{trigger: `{message: hello, not: {state: [{key: role, eq: user}]}}`},
+		{trigger: `{message: hello, any: [{if: [{value: "${state.balance}", lt: 100}]}]}`, ok: true},
+		{trigger: `{message: hello, any: [{if: [{value: "${state.missing}", lt: 100}]}]}`},
+		{trigger: `{message: hello, not: {if: [{value: "${state.missing}", lt: 100}]}}`, ok: true},
+	} {
+		t.Run(tc.trigger, func(t *testing.T) {
+			var tr spec.Trigger
+			require.NoError(t, yaml.Unmarshal([]byte(tc.trigger), &tr))
+			f, err := b.triggerFilter(&tr)
+			require.NoError(t, err)
+			ok, err := f.Check(ctx, upd)
+			require.NoError(t, err)
+			require.Equal(t, tc.ok, ok)
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package bot
+
+import (
+	"context"
+	"time"
+
+	"github.com/g4s8/openbots/internal/bot/handlers"
+	"github.com/g4s8/openbots/pkg/spec"
+	"github.com/g4s8/openbots/pkg/types"
+	"github.com/pkg/errors"
+)
+
+// contextSweepInterval is an interval to clear expired contexts.
+const contextSweepInterval = 5 * time.Second
+
+// contextSetter creates context setter handler from spec.
+func (b *Bot) contextSetter(s *spec.Context) (*handlers.SetContextHandler, error) {
+	if s.TTL != "" {
+		ttl, err := time.ParseDuration(s.TTL)
+		if err != nil {
+			return nil, errors.Wrapf(err, "parse context TTL %q", s.TTL)
+		}
+		b.cp.EnableExpiration()
+		return handlers.NewContextSetterWithTTL(b.cp, s.Set, ttl, b.log), nil
+	}
+	return handlers.NewContextSetter(b.cp, s.Set, b.log), nil
+}
+
+// runContextSweeper clears expired contexts and runs `contextExpired` handlers
+// until the bot is stopped.
+func (b *Bot) runContextSweeper() {
+	defer b.background.Done()
+	log := b.log.With().Str("component", "context_sweeper").Logger()
+	ticker := time.NewTicker(contextSweepInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-b.quitCh:
+			return
+		case now := <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+			expired, err := b.cp.Expire(ctx, now)
+			cancel()
+			if err != nil {
+				log.Error().Err(err).Msg("Expire contexts")
+				continue
+			}
+			for _, exp := range expired {
+				if err := b.handleExpiredContext(exp); err != nil {
+					log.Error().Err(err).Str("chat_id", exp.ChatID.String()).Str("context", exp.Value).
+						Msg("Handle expired context")
+				}
+			}
+		}
+	}
+}
+
+func (b *Bot) handleExpiredContext(exp types.ExpiredContext) error {
+	hs, ok := b.expired[exp.Value]
+	if !ok {
+		return nil
+	}
+	return b.handleScheduled(context.Background(), exp.ChatID, hs)
+}
@@ -0,0 +1,48 @@
+package bot
+
+import (
+	"context"
+	"time"
+
+	"github.com/g4s8/openbots/pkg/types"
+)
+
+// delayedPollInterval is an interval to check due delayed jobs.
+const delayedPollInterval = time.Second
+
+// runDelayed polls due delayed jobs and runs their handlers until the bot is stopped.
+func (b *Bot) runDelayed() {
+	defer b.background.Done()
+	log := b.log.With().Str("component", "delayed").Logger()
+	ticker := time.NewTicker(delayedPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-b.quitCh:
+			return
+		case now := <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+			due, err := b.jobs.Due(ctx, now)
+			cancel()
+			if err != nil {
+				log.Error().Err(err).Msg("Get due jobs")
+				continue
+			}
+			for _, job := range due {
+				if err := b.handleJob(job); err != nil {
+					log.Error().Err(err).Str("chat_id", job.ChatID.String()).Str("event", job.Event).
+						Msg("Handle delayed event")
+				}
+			}
+		}
+	}
+}
+
+func (b *Bot) handleJob(job types.Job) error {
+	hs, ok := b.delayed[job.Event]
+	if !ok {
+		b.log.Warn().Str("event", job.Event).Msg("No handlers for delayed event")
+		return nil
+	}
+	return b.handleScheduled(context.Background(), job.ChatID, hs)
+}
@@ -2,8 +2,10 @@ package bot
 
 import (
 	"net/http"
+	"time"
 
 	botctx "github.com/g4s8/openbots/internal/bot/ctx"
+	"github.com/g4s8/openbots/pkg/api"
 	"github.com/g4s8/openbots/pkg/types"
 	"github.com/rs/zerolog"
 )
@@ -60,9 +62,45 @@ func WithPaymentProviders(payments types.PaymentProviders) Option {
 	}
 }
 
+// WithJobsProvider option sets delayed jobs provider for bot.
+func WithJobsProvider(jobs types.JobsProvider) Option {
+	return func(b *Bot) {
+		b.jobs = jobs
+	}
+}
+
+// WithPollsProvider option sets provider of polls sent by bot.
+func WithPollsProvider(polls types.PollsProvider) Option {
+	return func(b *Bot) {
+		b.polls = polls
+	}
+}
+
 // WithSecrets option sets secrets provider for bot.
 func WithSecrets(secrets types.Secrets) Option {
 	return func(b *Bot) {
 		b.secrets = secrets
 	}
 }
+
+// WithWebApp enables Mini App endpoints of API service,
+// bot token and assets are used if not set in config.
+func WithWebApp(cfg api.WebAppConfig) Option {
+	return func(b *Bot) {
+		b.webApp = &cfg
+	}
+}
+
+// WithAdminChat option sets default target chat of forward and copy replies.
+func WithAdminChat(chat string) Option {
+	return func(b *Bot) {
+		b.adminChat = chat
+	}
+}
+
+// WithUpdateTimeout option sets maximum time to handle one update.
+func WithUpdateTimeout(timeout time.Duration) Option {
+	return func(b *Bot) {
+		b.updateTimeout = timeout
+	}
+}
@@ -0,0 +1,154 @@
+package bot
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"strings"
+
+	"github.com/g4s8/openbots/pkg/spec"
+	"github.com/g4s8/openbots/pkg/state"
+	"github.com/g4s8/openbots/pkg/types"
+	telegram "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/pkg/errors"
+)
+
+// commandScopeTypes maps spec command scopes to telegram scope types.
+var commandScopeTypes = map[string]string{
+	spec.CommandScopeDefault: "default",
+	spec.CommandScopePrivate: "all_private_chats",
+	spec.CommandScopeGroups:  "all_group_chats",
+	spec.CommandScopeAdmins:  "all_chat_administrators",
+}
+
+// commandLanguagesKey is a state key of the bot chat with languages
+// of synced commands menus, it's used to delete menus of removed languages.
+const commandLanguagesKey = "commands.languages"
+
+// selfChat is a chat of the bot itself, its state keeps bot settings.
+func (b *Bot) selfChat() types.ChatID {
+	return types.ChatID(b.botAPI.Self.ID)
+}
+
+// SyncCommands updates commands menu of the bot for all scopes and languages
+// of commands. Menus are changed only if they differ from current menus.
+// Menus of languages removed since the previous sync are deleted, if state
+// provider keeps the state between restarts.
+func (b *Bot) SyncCommands(cmds []*spec.Command) error {
+	ctx := context.Background()
+	st := state.NewUserState()
+	defer st.Close()
+	if err := b.state.Load(ctx, b.selfChat(), st); err != nil {
+		return errors.Wrap(err, "load commands languages")
+	}
+	menus := spec.CommandMenus(cmds)
+	var langs []string
+	for _, menu := range menus {
+		if menu.Language != "" && !slices.Contains(langs, menu.Language) {
+			langs = append(langs, menu.Language)
+		}
+	}
+	if synced, ok := st.Get(commandLanguagesKey); ok {
+		for _, lang := range strings.Split(synced, ",") {
+			if lang == "" || slices.Contains(langs, lang) {
+				continue
+			}
+			for _, menu := range menus {
+				if menu.Language == "" {
+					menus = append(menus, spec.CommandMenu{Scope: menu.Scope, Language: lang})
+				}
+			}
+		}
+	}
+
+	for _, menu := range menus {
+		scope := &telegram.BotCommandScope{Type: commandScopeTypes[menu.Scope]}
+		want := make([]telegram.BotCommand, len(menu.Items))
+		for i, item := range menu.Items {
+			want[i] = telegram.BotCommand{Command: item.Command, Description: item.Description}
+		}
+		current, err := b.botAPI.GetMyCommandsWithConfig(telegram.GetMyCommandsConfig{
+			Scope: scope, LanguageCode: menu.Language,
+		})
+		if err != nil {
+			return errors.Wrapf(err, "get commands of scope %q", menu.Scope)
+		}
+		if slices.Equal(current, want) {
+			continue
+		}
+		log := b.log.Info().Str("scope", menu.Scope).Str("language", menu.Language)
+		if len(want) == 0 {
+			if _, err := b.botAPI.Request(telegram.DeleteMyCommandsConfig{
+				Scope: scope, LanguageCode: menu.Language,
+			}); err != nil {
+				return errors.Wrapf(err, "delete commands of scope %q", menu.Scope)
+			}
+			log.Msg("Commands menu deleted")
+			continue
+		}
+		if _, err := b.botAPI.Request(telegram.SetMyCommandsConfig{
+			Commands: want, Scope: scope, LanguageCode: menu.Language,
+		}); err != nil {
+			return errors.Wrapf(err, "set commands of scope %q", menu.Scope)
+		}
+		log.Int("commands", len(want)).Msg("Commands menu updated")
+	}
+
+	st.Set(commandLanguagesKey, strings.Join(langs, ","))
+	if err := b.state.Update(ctx, b.selfChat(), st); err != nil {
+		return errors.Wrap(err, "update commands languages")
+	}
+	return nil
+}
+
+// profileField is a bot profile field with get and set methods.
+type profileField struct {
+	name   string
+	getter string
+	setter string
+	value  func(spec.ProfileInfo) string
+}
+
+var profileFields = []profileField{
+	{"name", "getMyName", "setMyName", func(p spec.ProfileInfo) string { return p.Name }},
+	{"description", "getMyDescription", "setMyDescription", func(p spec.ProfileInfo) string { return p.Description }},
+	{"short_description", "getMyShortDescription", "setMyShortDescription",
+		func(p spec.ProfileInfo) string { return p.ShortDescription }},
+}
+
+// SyncProfile updates name and descriptions of the bot for default and profile languages,
+// fields are changed only if they are not empty and differ from current values.
+// It uses raw requests, since telegram library doesn't support these methods.
+func (b *Bot) SyncProfile(p *spec.Profile) error {
+	infos := map[string]spec.ProfileInfo{"": p.ProfileInfo}
+	for lang, info := range p.Languages {
+		infos[lang] = info
+	}
+	for lang, info := range infos {
+		for _, f := range profileFields {
+			want := f.value(info)
+			if want == "" {
+				continue
+			}
+			params := make(telegram.Params)
+			params.AddNonEmpty("language_code", lang)
+			resp, err := b.botAPI.MakeRequest(f.getter, params)
+			if err != nil {
+				return errors.Wrapf(err, "get bot %s", f.name)
+			}
+			var current map[string]string
+			if err := json.Unmarshal(resp.Result, &current); err != nil {
+				return errors.Wrapf(err, "decode bot %s", f.name)
+			}
+			if current[f.name] == want {
+				continue
+			}
+			params[f.name] = want
+			if _, err := b.botAPI.MakeRequest(f.setter, params); err != nil {
+				return errors.Wrapf(err, "set bot %s", f.name)
+			}
+			b.log.Info().Str("field", f.name).Str("language", lang).Msg("Bot profile updated")
+		}
+	}
+	return nil
+}
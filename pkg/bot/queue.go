@@ -0,0 +1,65 @@
+package bot
+
+import (
+	"sync"
+
+	"github.com/g4s8/openbots/pkg/types"
+)
+
+// chatQueue runs tasks of the same chat one by one in submission order,
+// and tasks of different chats concurrently. Each chat with pending tasks
+// has one worker goroutine, which exits when the chat has no more tasks.
+type chatQueue struct {
+	mx    sync.Mutex
+	tasks map[types.ChatID][]func()
+	wg    sync.WaitGroup
+}
+
+func newChatQueue() *chatQueue {
+	return &chatQueue{tasks: make(map[types.ChatID][]func())}
+}
+
+// Go submits the task of the chat without waiting for it.
+func (q *chatQueue) Go(chatID types.ChatID, task func()) {
+	q.mx.Lock()
+	defer q.mx.Unlock()
+	pending, active := q.tasks[chatID]
+	q.tasks[chatID] = append(pending, task)
+	if active {
+		return
+	}
+	q.wg.Add(1)
+	go q.run(chatID)
+}
+
+// Do submits the task of the chat and waits until it's completed.
+func (q *chatQueue) Do(chatID types.ChatID, task func()) {
+	done := make(chan struct{})
+	q.Go(chatID, func() {
+		defer close(done)
+		task()
+	})
+	<-done
+}
+
+// Wait until all submitted tasks are completed.
+func (q *chatQueue) Wait() {
+	q.wg.Wait()
+}
+
+func (q *chatQueue) run(chatID types.ChatID) {
+	defer q.wg.Done()
+	for {
+		q.mx.Lock()
+		pending := q.tasks[chatID]
+		if len(pending) == 0 {
+			delete(q.tasks, chatID)
+			q.mx.Unlock()
+			return
+		}
+		task := pending[0]
+		q.tasks[chatID] = pending[1:]
+		q.mx.Unlock()
+		task()
+	}
+}
@@ -0,0 +1,52 @@
+package bot
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/g4s8/openbots/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChatQueue(t *testing.T) {
+	t.Run("same chat in order", func(t *testing.T) {
+		q := newChatQueue()
+		var (
+			mx  sync.Mutex
+			res []int
+		)
+		for i := 0; i < 10; i++ {
+			i := i
+			q.Go(types.ChatID(1), func() {
+				mx.Lock()
+				res = append(res, i)
+				mx.Unlock()
+			})
+		}
+		q.Wait()
+		require.Equal(t, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, res)
+	})
+	t.Run("other chats are not blocked", func(t *testing.T) {
+		q := newChatQueue()
+		release := make(chan struct{})
+		q.Go(types.ChatID(1), func() { <-release })
+		done := make(chan struct{})
+		q.Go(types.ChatID(2), func() { close(done) })
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("chat 2 is blocked by chat 1")
+		}
+		close(release)
+		q.Wait()
+	})
+	t.Run("do waits for task", func(t *testing.T) {
+		q := newChatQueue()
+		var called bool
+		q.Do(types.ChatID(1), func() { called = true })
+		require.True(t, called)
+		q.Wait()
+		require.Empty(t, q.tasks)
+	})
+}
@@ -0,0 +1,128 @@
+package bot
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/g4s8/openbots/internal/bot/updates"
+	"github.com/g4s8/openbots/pkg/spec"
+	"github.com/g4s8/openbots/pkg/types"
+	"github.com/pkg/errors"
+
+	telegram "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// scheduleTimeout is a maximum time to run scheduled handlers for all chats.
+const scheduleTimeout = 5 * time.Minute
+
+// scheduledJob runs handlers for chats by cron schedule.
+type scheduledJob struct {
+	bot      *Bot
+	chats    []types.ChatID
+	handlers []*eventHandler
+}
+
+// schedule registers handlers to run by cron expression of the trigger.
+func (b *Bot) schedule(s *spec.ScheduleTrigger, hs []*eventHandler) error {
+	job := &scheduledJob{bot: b, handlers: hs}
+	for _, id := range s.Chats {
+		job.chats = append(job.chats, types.ChatID(id))
+	}
+	if len(job.chats) == 0 {
+		if _, ok := b.state.(types.ChatLister); !ok {
+			return errors.New("state provider doesn't support chats listing")
+		}
+	}
+	if _, err := b.cron.AddJob(s.Cron, job); err != nil {
+		return errors.Wrapf(err, "add schedule %q", s.Cron)
+	}
+	return nil
+}
+
+func (j *scheduledJob) Run() {
+	log := j.bot.log.With().Str("component", "scheduler").Logger()
+	ctx, cancel := context.WithTimeout(context.Background(), scheduleTimeout)
+	defer cancel()
+
+	chats := j.chats
+	if len(chats) == 0 {
+		var err error
+		chats, err = j.bot.state.(types.ChatLister).Chats(ctx)
+		if err != nil {
+			log.Error().Err(err).Msg("List chats")
+			return
+		}
+		// the bot chat state keeps bot settings, it's not a real chat,
+		// and the support operators chat state keeps relayed messages
+		chats = slices.DeleteFunc(chats, func(id types.ChatID) bool {
+			return id == j.bot.selfChat() || (j.bot.supportChat != 0 && id == j.bot.supportChat)
+		})
+	}
+	log.Debug().Int("chats", len(chats)).Msg("Running scheduled handlers")
+	for _, chatID := range chats {
+		if err := j.handle(ctx, chatID); err != nil {
+			log.Error().Err(err).Str("chat_id", chatID.String()).Msg("Handle scheduled update")
+		}
+		if ctx.Err() != nil {
+			log.Warn().Err(ctx.Err()).Msg("Scheduled handlers interrupted")
+			return
+		}
+	}
+}
+
+func (j *scheduledJob) handle(ctx context.Context, chatID types.ChatID) error {
+	return j.bot.handleScheduled(ctx, chatID, j.handlers)
+}
+
+// handleScheduled runs handlers for synthetic update of the chat. It waits
+// for updates of the chat being handled, so handlers don't race with them
+// on chat context and state.
+func (b *Bot) handleScheduled(ctx context.Context, chatID types.ChatID, hs []*eventHandler) error {
+	var err error
+	b.queue.Do(chatID, func() {
+		ctx, cancel := context.WithTimeout(ctx, b.updateTimeout)
+		defer cancel()
+		err = b.handleUpdate(ctx, b.scheduledUpdate(chatID), hs)
+	})
+	return err
+}
+
+// scheduledUpdate creates synthetic update for the chat, so scheduled
+// handlers could reply to the chat as for a regular message. The chat
+// is loaded from telegram to match chat type triggers, and the chat user
+// is a sender of the update in private chats to match sender triggers.
+func (b *Bot) scheduledUpdate(chatID types.ChatID) *updates.Update {
+	chat := b.chat(chatID)
+	msg := &telegram.Message{
+		Chat: &chat,
+		Date: int(time.Now().Unix()),
+	}
+	if chat.IsPrivate() {
+		msg.From = &telegram.User{
+			ID:        chat.ID,
+			FirstName: chat.FirstName,
+			LastName:  chat.LastName,
+			UserName:  chat.UserName,
+		}
+	}
+	return &updates.Update{Update: telegram.Update{Message: msg}}
+}
+
+// chat returns chat info by ID, it's loaded from telegram once and cached,
+// so scheduled handlers don't request each chat on every run.
+func (b *Bot) chat(chatID types.ChatID) telegram.Chat {
+	if chat, ok := b.chats.Load(chatID); ok {
+		return chat.(telegram.Chat)
+	}
+	chat, err := b.botAPI.GetChat(telegram.ChatInfoConfig{
+		ChatConfig: telegram.ChatConfig{ChatID: chatID.Int64()},
+	})
+	if err != nil {
+		b.log.Warn().Err(err).Str("chat_id", chatID.String()).Msg("Get chat of scheduled update")
+		// don't cache the chat to load it on next run
+		return telegram.Chat{ID: chatID.Int64()}
+	}
+	b.chats.Store(chatID, chat)
+	return chat
+}
@@ -0,0 +1,69 @@
+package bot
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	"github.com/g4s8/openbots/pkg/state"
+	"github.com/g4s8/openbots/pkg/types"
+	telegram "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/stretchr/testify/require"
+)
+
+// chatsRecorder records chats of handled updates.
+type chatsRecorder struct {
+	chats []telegram.Chat
+}
+
+func (r *chatsRecorder) Handle(_ context.Context, upd *telegram.Update, _ *telegram.BotAPI) error {
+	r.chats = append(r.chats, *upd.Message.Chat)
+	return nil
+}
+
+func TestScheduledJob(t *testing.T) {
+	var getChat atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		var result any = true
+		switch path.Base(r.URL.Path) {
+		case "getMe":
+			result = telegram.User{ID: 1, IsBot: true, UserName: "test_bot"}
+		case "getChat":
+			getChat.Add(1)
+			id, _ := strconv.ParseInt(r.Form.Get("chat_id"), 10, 64)
+			result = telegram.Chat{ID: id, Type: "private", FirstName: "user"}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"ok": true, "result": result})
+	}))
+	t.Cleanup(srv.Close)
+	api, err := telegram.NewBotAPIWithClient("123456:ABC-DEF", srv.URL+"/bot%s/%s", srv.Client())
+	require.NoError(t, err)
+
+	b := NewWithOptions(api)
+	b.supportChat = -100
+	for _, id := range []types.ChatID{42, 43, b.selfChat(), b.supportChat} {
+		st := state.NewUserState()
+		st.Set("key", "value")
+		require.NoError(t, b.state.Update(context.Background(), id, st))
+		st.Close()
+	}
+	var r chatsRecorder
+	job := &scheduledJob{bot: b, handlers: []*eventHandler{{EventFilter: anyUpdate{}, Handler: &r}}}
+
+	job.Run()
+	job.Run()
+	var ids []int64
+	for _, chat := range r.chats {
+		ids = append(ids, chat.ID)
+		require.True(t, chat.IsPrivate())
+	}
+	require.ElementsMatch(t, []int64{42, 43, 42, 43}, ids, "bot and support chats are skipped")
+	require.EqualValues(t, 2, getChat.Load(), "chats are loaded once")
+}
@@ -3,12 +3,19 @@ package context
 import (
 	ctx "context"
 	"database/sql"
+	"time"
 
 	"github.com/g4s8/openbots/internal/db"
 	"github.com/g4s8/openbots/pkg/types"
 	"github.com/pkg/errors"
 )
 
+var (
+	_ types.ContextExpirer     = (*dbProvider)(nil)
+	_ types.ChatContextExpirer = (*dbProvider)(nil)
+	_ types.ExpiringContext    = (*dbContext)(nil)
+)
+
 type dbProvider struct {
 	db    *sql.DB
 	botID int64
@@ -18,10 +25,78 @@ func NewDBProvider(db *sql.DB, botID int64) types.ContextProvider {
 	return &dbProvider{db: db, botID: botID}
 }
 
+// InitDB adds `expires_at` column to `bot_context` table
+// if it was created before context TTL support.
+func InitDB(ctx ctx.Context, con *sql.DB) error {
+	if _, err := con.ExecContext(ctx,
+		`ALTER TABLE bot_context ADD COLUMN IF NOT EXISTS expires_at TIMESTAMP WITH TIME ZONE`); err != nil {
+		return errors.Wrap(err, "add context expiration column")
+	}
+	return nil
+}
+
 func (p *dbProvider) UserContext(chatID types.ChatID) types.Context {
 	return &dbContext{db: p.db, botID: p.botID, chatID: chatID}
 }
 
+func (p *dbProvider) Expire(ctx ctx.Context, now time.Time) ([]types.ExpiredContext, error) {
+	var res []types.ExpiredContext
+	if err := db.Transactional(p.db, ctx, nil, func(tx *sql.Tx) error {
+		rows, err := tx.QueryContext(ctx,
+			`DELETE FROM bot_context WHERE bot_id = $1 AND expires_at <= $2
+			RETURNING chat_id, value`,
+			p.botID, now.UTC())
+		if err != nil {
+			return errors.Wrap(err, "delete expired values")
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var (
+				chatID int64
+				value  string
+			)
+			if err := rows.Scan(&chatID, &value); err != nil {
+				return errors.Wrap(err, "scan value")
+			}
+			res = append(res, types.ExpiredContext{ChatID: types.ChatID(chatID), Value: value})
+		}
+		return rows.Err()
+	}); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (p *dbProvider) ExpireChat(ctx ctx.Context, chatID types.ChatID, now time.Time) (types.ExpiredContext, bool, error) {
+	var (
+		value string
+		found bool
+	)
+	if err := db.Transactional(p.db, ctx, nil, func(tx *sql.Tx) error {
+		rows, err := tx.QueryContext(ctx,
+			`DELETE FROM bot_context WHERE bot_id = $1 AND chat_id = $2 AND expires_at <= $3
+			RETURNING value`,
+			p.botID, chatID, now.UTC())
+		if err != nil {
+			return errors.Wrap(err, "delete expired value")
+		}
+		defer rows.Close()
+		if rows.Next() {
+			if err := rows.Scan(&value); err != nil {
+				return errors.Wrap(err, "scan value")
+			}
+			found = true
+		}
+		return rows.Err()
+	}); err != nil {
+		return types.ExpiredContext{}, false, err
+	}
+	if !found {
+		return types.ExpiredContext{}, false, nil
+	}
+	return types.ExpiredContext{ChatID: chatID, Value: value}, true, nil
+}
+
 type dbContext struct {
 	db     *sql.DB
 	botID  int64
@@ -29,11 +104,19 @@ type dbContext struct {
 }
 
 func (c *dbContext) Set(ctx ctx.Context, value string) error {
+	return c.set(ctx, value, sql.NullTime{})
+}
+
+func (c *dbContext) SetWithTTL(ctx ctx.Context, value string, ttl time.Duration) error {
+	return c.set(ctx, value, sql.NullTime{Time: time.Now().Add(ttl).UTC(), Valid: true})
+}
+
+func (c *dbContext) set(ctx ctx.Context, value string, expires sql.NullTime) error {
 	return db.Transactional(c.db, ctx, nil, func(tx *sql.Tx) error {
 		if _, err := tx.ExecContext(ctx,
-			`INSERT INTO bot_context (bot_id, chat_id, value) VALUES ($1, $2, $3)
-			ON CONFLICT (bot_id, chat_id) DO UPDATE SET value = $3`,
-			c.botID, c.chatID, value); err != nil {
+			`INSERT INTO bot_context (bot_id, chat_id, value, expires_at) VALUES ($1, $2, $3, $4)
+			ON CONFLICT (bot_id, chat_id) DO UPDATE SET value = $3, expires_at = $4`,
+			c.botID, c.chatID, value, expires); err != nil {
 			return errors.Wrap(err, "insert value")
 		}
 		return nil
@@ -55,7 +138,8 @@ func (c *dbContext) Check(ctx ctx.Context, value string) (bool, error) {
 	var result string
 	if err := db.Transactional(c.db, ctx, nil, func(tx *sql.Tx) error {
 		rows, err := tx.QueryContext(ctx,
-			`SELECT value FROM bot_context WHERE bot_id = $1 AND chat_id = $2`,
+			`SELECT value FROM bot_context WHERE bot_id = $1 AND chat_id = $2
+			AND (expires_at IS NULL OR expires_at > now())`,
 			c.botID, c.chatID)
 		if err != nil {
 			return errors.Wrap(err, "select value")
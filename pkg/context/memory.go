@@ -3,18 +3,34 @@ package context
 import (
 	ctx "context"
 	"sync"
+	"time"
 
 	"github.com/g4s8/openbots/pkg/types"
 )
 
+var (
+	_ types.ContextExpirer     = (*memoryProvider)(nil)
+	_ types.ChatContextExpirer = (*memoryProvider)(nil)
+	_ types.ExpiringContext    = (*memoryContext)(nil)
+)
+
+type memoryValue struct {
+	value   string
+	expires time.Time
+}
+
+func (v memoryValue) expired(now time.Time) bool {
+	return !v.expires.IsZero() && !v.expires.After(now)
+}
+
 type memoryProvider struct {
-	values map[types.ChatID]string
+	values map[types.ChatID]memoryValue
 	mux    sync.RWMutex
 }
 
 func NewMemoryProvider() types.ContextProvider {
 	return &memoryProvider{
-		values: make(map[types.ChatID]string),
+		values: make(map[types.ChatID]memoryValue),
 	}
 }
 
@@ -25,16 +41,45 @@ func (mp *memoryProvider) UserContext(uid types.ChatID) types.Context {
 	}
 }
 
-func (mp *memoryProvider) set(uid types.ChatID, value string) {
+func (mp *memoryProvider) Expire(_ ctx.Context, now time.Time) ([]types.ExpiredContext, error) {
 	mp.mux.Lock()
 	defer mp.mux.Unlock()
-	mp.values[uid] = value
+
+	var res []types.ExpiredContext
+	for uid, v := range mp.values {
+		if v.expired(now) {
+			res = append(res, types.ExpiredContext{ChatID: uid, Value: v.value})
+			delete(mp.values, uid)
+		}
+	}
+	return res, nil
+}
+
+func (mp *memoryProvider) ExpireChat(_ ctx.Context, uid types.ChatID, now time.Time) (types.ExpiredContext, bool, error) {
+	mp.mux.Lock()
+	defer mp.mux.Unlock()
+
+	v, ok := mp.values[uid]
+	if !ok || !v.expired(now) {
+		return types.ExpiredContext{}, false, nil
+	}
+	delete(mp.values, uid)
+	return types.ExpiredContext{ChatID: uid, Value: v.value}, true, nil
+}
+
+func (mp *memoryProvider) set(uid types.ChatID, value string, expires time.Time) {
+	mp.mux.Lock()
+	defer mp.mux.Unlock()
+	mp.values[uid] = memoryValue{value: value, expires: expires}
 }
 
 func (mp *memoryProvider) get(uid types.ChatID) string {
 	mp.mux.RLock()
 	defer mp.mux.RUnlock()
-	return mp.values[uid]
+	if v := mp.values[uid]; !v.expired(time.Now()) {
+		return v.value
+	}
+	return ""
 }
 
 func (mp *memoryProvider) reset(uid types.ChatID) {
@@ -49,7 +94,12 @@ type memoryContext struct {
 }
 
 func (c *memoryContext) Set(_ ctx.Context, value string) error {
-	c.provider.set(c.uid, value)
+	c.provider.set(c.uid, value, time.Time{})
+	return nil
+}
+
+func (c *memoryContext) SetWithTTL(_ ctx.Context, value string, ttl time.Duration) error {
+	c.provider.set(c.uid, value, time.Now().Add(ttl))
 	return nil
 }
 
@@ -0,0 +1,89 @@
+package jobs
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/g4s8/openbots/internal/db"
+	"github.com/g4s8/openbots/pkg/types"
+	"github.com/pkg/errors"
+)
+
+var _ types.JobsProvider = (*DB)(nil)
+
+// DB keeps jobs in `bot_jobs` table.
+type DB struct {
+	con   *sql.DB
+	botID int64
+}
+
+func NewDB(con *sql.DB, botID int64) *DB {
+	return &DB{con: con, botID: botID}
+}
+
+// Init creates `bot_jobs` table if it doesn't exist.
+func (d *DB) Init(ctx context.Context) error {
+	if _, err := d.con.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS bot_jobs (
+		id SERIAL PRIMARY KEY,
+		bot_id BIGINT NOT NULL,
+		chat_id BIGINT NOT NULL,
+		event TEXT NOT NULL,
+		run_at TIMESTAMP WITH TIME ZONE NOT NULL
+	)`); err != nil {
+		return errors.Wrap(err, "create jobs table")
+	}
+	if _, err := d.con.ExecContext(ctx,
+		`CREATE INDEX IF NOT EXISTS bot_jobs_run_at ON bot_jobs(bot_id, run_at)`); err != nil {
+		return errors.Wrap(err, "create jobs index")
+	}
+	return nil
+}
+
+func (d *DB) Add(ctx context.Context, job types.Job) error {
+	if _, err := d.con.ExecContext(ctx,
+		`INSERT INTO bot_jobs(bot_id, chat_id, event, run_at) VALUES($1, $2, $3, $4)`,
+		d.botID, int64(job.ChatID), job.Event, job.At.UTC()); err != nil {
+		return errors.Wrap(err, "insert job")
+	}
+	return nil
+}
+
+func (d *DB) Cancel(ctx context.Context, chatID types.ChatID, event string) error {
+	if _, err := d.con.ExecContext(ctx,
+		`DELETE FROM bot_jobs WHERE bot_id = $1 AND chat_id = $2 AND event = $3`,
+		d.botID, int64(chatID), event); err != nil {
+		return errors.Wrap(err, "delete jobs")
+	}
+	return nil
+}
+
+func (d *DB) Due(ctx context.Context, now time.Time) ([]types.Job, error) {
+	var res []types.Job
+	err := db.Transactional(d.con, ctx, nil, func(tx *sql.Tx) error {
+		rows, err := tx.QueryContext(ctx,
+			`DELETE FROM bot_jobs WHERE bot_id = $1 AND run_at <= $2
+			RETURNING chat_id, event, run_at`,
+			d.botID, now.UTC())
+		if err != nil {
+			return errors.Wrap(err, "delete due jobs")
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var (
+				chatID int64
+				job    types.Job
+			)
+			if err := rows.Scan(&chatID, &job.Event, &job.At); err != nil {
+				return errors.Wrap(err, "scan job")
+			}
+			job.ChatID = types.ChatID(chatID)
+			res = append(res, job)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
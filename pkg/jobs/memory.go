@@ -0,0 +1,64 @@
+// Package jobs provides storage for delayed jobs.
+package jobs
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/g4s8/openbots/pkg/types"
+)
+
+var _ types.JobsProvider = (*Memory)(nil)
+
+// Memory keeps jobs in memory, all jobs are lost on restart.
+type Memory struct {
+	jobs []types.Job
+	mux  sync.Mutex
+}
+
+func NewMemory() *Memory {
+	return &Memory{}
+}
+
+func (m *Memory) Add(_ context.Context, job types.Job) error {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	m.jobs = append(m.jobs, job)
+	return nil
+}
+
+func (m *Memory) Cancel(_ context.Context, chatID types.ChatID, event string) error {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	m.jobs = m.filter(func(j types.Job) bool {
+		return j.ChatID == chatID && j.Event == event
+	}, nil)
+	return nil
+}
+
+func (m *Memory) Due(_ context.Context, now time.Time) ([]types.Job, error) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	var res []types.Job
+	m.jobs = m.filter(func(j types.Job) bool {
+		return !j.At.After(now)
+	}, &res)
+	return res, nil
+}
+
+// filter removes jobs matched by predicate and appends them to removed if not nil.
+func (m *Memory) filter(pred func(types.Job) bool, removed *[]types.Job) []types.Job {
+	keep := m.jobs[:0]
+	for _, j := range m.jobs {
+		if !pred(j) {
+			keep = append(keep, j)
+		} else if removed != nil {
+			*removed = append(*removed, j)
+		}
+	}
+	return keep
+}
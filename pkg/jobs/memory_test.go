@@ -0,0 +1,37 @@
+package jobs
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/g4s8/openbots/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemory(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	mem := NewMemory()
+	require.NoError(t, mem.Add(ctx, types.Job{ChatID: 1, Event: "remind", At: now.Add(time.Hour)}))
+	require.NoError(t, mem.Add(ctx, types.Job{ChatID: 1, Event: "nudge", At: now.Add(time.Minute)}))
+	require.NoError(t, mem.Add(ctx, types.Job{ChatID: 2, Event: "nudge", At: now.Add(time.Minute)}))
+
+	due, err := mem.Due(ctx, now)
+	require.NoError(t, err)
+	require.Empty(t, due)
+
+	require.NoError(t, mem.Cancel(ctx, 1, "nudge"))
+	due, err = mem.Due(ctx, now.Add(2*time.Minute))
+	require.NoError(t, err)
+	require.Equal(t, []types.Job{{ChatID: 2, Event: "nudge", At: now.Add(time.Minute)}}, due)
+
+	due, err = mem.Due(ctx, now.Add(2*time.Minute))
+	require.NoError(t, err)
+	require.Empty(t, due)
+
+	due, err = mem.Due(ctx, now.Add(2*time.Hour))
+	require.NoError(t, err)
+	require.Len(t, due, 1)
+	require.Equal(t, "remind", due[0].Event)
+}
@@ -2,7 +2,9 @@ package log
 
 import (
 	ctx "context"
+	"time"
 
+	botctx "github.com/g4s8/openbots/internal/bot/ctx"
 	"github.com/g4s8/openbots/pkg/types"
 	"github.com/rs/zerolog"
 )
@@ -23,6 +25,30 @@ func (c *ContextProvider) UserContext(chatID types.ChatID) types.Context {
 	return WrapContext(c.base.UserContext(chatID), c.log.With().Str("chat", chatID.String()).Logger())
 }
 
+func (c *ContextProvider) Expire(ctx ctx.Context, now time.Time) ([]types.ExpiredContext, error) {
+	expirer, ok := c.base.(types.ContextExpirer)
+	if !ok {
+		return nil, nil
+	}
+	res, err := expirer.Expire(ctx, now)
+	if len(res) > 0 {
+		c.log.Debug().Int("count", len(res)).Msg("Expire contexts")
+	}
+	return res, err
+}
+
+func (c *ContextProvider) ExpireChat(ctx ctx.Context, chatID types.ChatID, now time.Time) (types.ExpiredContext, bool, error) {
+	expirer, ok := c.base.(types.ChatContextExpirer)
+	if !ok {
+		return types.ExpiredContext{}, false, nil
+	}
+	res, ok, err := expirer.ExpireChat(ctx, chatID, now)
+	if ok {
+		c.log.Debug().Str("chat", chatID.String()).Str("val", res.Value).Msg("Expire context")
+	}
+	return res, ok, err
+}
+
 type Context struct {
 	base types.Context
 	log  zerolog.Logger
@@ -40,6 +66,15 @@ func (c *Context) Set(ctx ctx.Context, val string) error {
 	return c.base.Set(ctx, val)
 }
 
+func (c *Context) SetWithTTL(ctx ctx.Context, val string, ttl time.Duration) error {
+	ec, ok := c.base.(types.ExpiringContext)
+	if !ok {
+		return botctx.ErrTTLNotSupported
+	}
+	c.log.Debug().Str("val", val).Dur("ttl", ttl).Msg("Set context with TTL")
+	return ec.SetWithTTL(ctx, val, ttl)
+}
+
 func (c *Context) Reset(ctx ctx.Context) error {
 	c.log.Debug().Msg("Reset context")
 	return c.base.Reset(ctx)
@@ -2,6 +2,7 @@ package log
 
 import (
 	"context"
+	"errors"
 
 	"github.com/g4s8/openbots/pkg/types"
 	"github.com/rs/zerolog"
@@ -28,3 +29,15 @@ func (s *StateProvider) Update(ctx context.Context, chatID types.ChatID, state t
 	s.log.Debug().Str("chat", chatID.String()).Msg("Update state")
 	return s.base.Update(ctx, chatID, state)
 }
+
+// ErrChatsNotSupported is returned if base provider doesn't implement types.ChatLister.
+var ErrChatsNotSupported = errors.New("state provider doesn't support chats listing")
+
+func (s *StateProvider) Chats(ctx context.Context) ([]types.ChatID, error) {
+	lister, ok := s.base.(types.ChatLister)
+	if !ok {
+		return nil, ErrChatsNotSupported
+	}
+	s.log.Debug().Msg("List chats")
+	return lister.Chats(ctx)
+}
@@ -0,0 +1,85 @@
+package polls
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/g4s8/openbots/pkg/types"
+	"github.com/pkg/errors"
+)
+
+var _ types.PollsProvider = (*DB)(nil)
+
+// DB keeps polls in `bot_polls` table, closed polls are removed.
+type DB struct {
+	con   *sql.DB
+	botID int64
+}
+
+func NewDB(con *sql.DB, botID int64) *DB {
+	return &DB{con: con, botID: botID}
+}
+
+// Init creates `bot_polls` table if it doesn't exist.
+func (d *DB) Init(ctx context.Context) error {
+	if _, err := d.con.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS bot_polls (
+		bot_id BIGINT NOT NULL,
+		poll_id TEXT NOT NULL,
+		chat_id BIGINT NOT NULL,
+		message_id INTEGER NOT NULL,
+		name TEXT NOT NULL,
+		options TEXT NOT NULL,
+		correct_option INTEGER NOT NULL,
+		PRIMARY KEY (bot_id, poll_id)
+	)`); err != nil {
+		return errors.Wrap(err, "create polls table")
+	}
+	return nil
+}
+
+func (d *DB) Add(ctx context.Context, poll types.Poll) error {
+	options, err := json.Marshal(poll.Options)
+	if err != nil {
+		return errors.Wrap(err, "encode poll options")
+	}
+	if _, err := d.con.ExecContext(ctx,
+		`INSERT INTO bot_polls(bot_id, poll_id, chat_id, message_id, name, options, correct_option)
+		VALUES($1, $2, $3, $4, $5, $6, $7)`,
+		d.botID, poll.ID, int64(poll.ChatID), poll.MessageID, poll.Name, string(options),
+		poll.CorrectOption); err != nil {
+		return errors.Wrap(err, "insert poll")
+	}
+	return nil
+}
+
+func (d *DB) Get(ctx context.Context, id string) (types.Poll, error) {
+	var (
+		poll    = types.Poll{ID: id}
+		chatID  int64
+		options string
+	)
+	err := d.con.QueryRowContext(ctx,
+		`SELECT chat_id, message_id, name, options, correct_option
+		FROM bot_polls WHERE bot_id = $1 AND poll_id = $2`,
+		d.botID, id).Scan(&chatID, &poll.MessageID, &poll.Name, &options, &poll.CorrectOption)
+	if errors.Is(err, sql.ErrNoRows) {
+		return types.Poll{}, types.ErrPollNotFound
+	}
+	if err != nil {
+		return types.Poll{}, errors.Wrap(err, "select poll")
+	}
+	if err := json.Unmarshal([]byte(options), &poll.Options); err != nil {
+		return types.Poll{}, errors.Wrap(err, "decode poll options")
+	}
+	poll.ChatID = types.ChatID(chatID)
+	return poll, nil
+}
+
+func (d *DB) Remove(ctx context.Context, id string) error {
+	if _, err := d.con.ExecContext(ctx,
+		`DELETE FROM bot_polls WHERE bot_id = $1 AND poll_id = $2`, d.botID, id); err != nil {
+		return errors.Wrap(err, "delete poll")
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+// Package polls provides storage for polls sent by the bot.
+package polls
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/g4s8/openbots/pkg/types"
+)
+
+var _ types.PollsProvider = (*Memory)(nil)
+
+// MemoryLimit is a max number of polls kept in memory,
+// the oldest polls are evicted when it's exceeded.
+const MemoryLimit = 10000
+
+// Memory keeps polls in memory, all polls are lost on restart.
+// Closed polls are removed, and the oldest polls are evicted
+// if there are more than limit open polls.
+type Memory struct {
+	polls map[string]types.Poll
+	// order of poll IDs from the oldest to the newest.
+	order []string
+	limit int
+	mux   sync.RWMutex
+}
+
+func NewMemory() *Memory {
+	return NewMemoryWithLimit(MemoryLimit)
+}
+
+// NewMemoryWithLimit creates memory polls storage with max number of polls.
+func NewMemoryWithLimit(limit int) *Memory {
+	return &Memory{polls: make(map[string]types.Poll), limit: limit}
+}
+
+func (m *Memory) Add(_ context.Context, poll types.Poll) error {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	if _, ok := m.polls[poll.ID]; !ok {
+		m.order = append(m.order, poll.ID)
+	}
+	m.polls[poll.ID] = poll
+	for len(m.order) > m.limit {
+		delete(m.polls, m.order[0])
+		m.order = m.order[1:]
+	}
+	return nil
+}
+
+func (m *Memory) Get(_ context.Context, id string) (types.Poll, error) {
+	m.mux.RLock()
+	defer m.mux.RUnlock()
+
+	poll, ok := m.polls[id]
+	if !ok {
+		return types.Poll{}, types.ErrPollNotFound
+	}
+	return poll, nil
+}
+
+func (m *Memory) Remove(_ context.Context, id string) error {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	if _, ok := m.polls[id]; !ok {
+		return nil
+	}
+	delete(m.polls, id)
+	m.order = slices.DeleteFunc(m.order, func(s string) bool { return s == id })
+	return nil
+}
@@ -0,0 +1,51 @@
+package polls
+
+import (
+	"context"
+	"testing"
+
+	"github.com/g4s8/openbots/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemory(t *testing.T) {
+	ctx := context.Background()
+	mem := NewMemory()
+	quiz := types.Poll{ID: "1", ChatID: 42, MessageID: 7, Name: "capital",
+		Options: []string{"Paris", "Rome"}, CorrectOption: 1}
+	require.NoError(t, mem.Add(ctx, quiz))
+	require.NoError(t, mem.Add(ctx, types.Poll{ID: "2", ChatID: 42, Name: "lunch", CorrectOption: -1}))
+
+	poll, err := mem.Get(ctx, "1")
+	require.NoError(t, err)
+	require.Equal(t, quiz, poll)
+	require.True(t, poll.Quiz())
+
+	poll, err = mem.Get(ctx, "2")
+	require.NoError(t, err)
+	require.False(t, poll.Quiz())
+
+	_, err = mem.Get(ctx, "3")
+	require.ErrorIs(t, err, types.ErrPollNotFound)
+}
+
+func TestMemoryEviction(t *testing.T) {
+	ctx := context.Background()
+	mem := NewMemoryWithLimit(2)
+	for _, id := range []string{"1", "2", "3"} {
+		require.NoError(t, mem.Add(ctx, types.Poll{ID: id, ChatID: 42}))
+	}
+	_, err := mem.Get(ctx, "1")
+	require.ErrorIs(t, err, types.ErrPollNotFound, "oldest poll is evicted")
+	_, err = mem.Get(ctx, "3")
+	require.NoError(t, err)
+
+	require.NoError(t, mem.Remove(ctx, "2"))
+	require.NoError(t, mem.Remove(ctx, "2"), "remove missing poll")
+	_, err = mem.Get(ctx, "2")
+	require.ErrorIs(t, err, types.ErrPollNotFound)
+
+	require.NoError(t, mem.Add(ctx, types.Poll{ID: "4", ChatID: 42}))
+	_, err = mem.Get(ctx, "3")
+	require.NoError(t, err, "removed poll frees the space")
+}
@@ -12,6 +12,8 @@ type ApiHandler struct {
 	ID string `yaml:"id"`
 	// Actions to perform.
 	Actions []*ApiAction `yaml:"actions"`
+	// WebApp allows Mini Apps to call the handler.
+	WebApp bool `yaml:"webApp"`
 }
 
 // ApiAction to perform.
@@ -0,0 +1,41 @@
+package spec
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// chatActions supported by telegram.
+var chatActions = []string{
+	"typing", "upload_photo", "record_video", "upload_video", "record_voice",
+	"upload_voice", "upload_document", "choose_sticker", "find_location",
+	"record_video_note", "upload_video_note",
+}
+
+func (r *Reply) validatePacing() []error {
+	var errs []error
+	if r.Typing && r.ChatAction != "" {
+		errs = append(errs, errors.New("both typing and chat action"))
+	}
+	if r.ChatAction != "" && !slices.Contains(chatActions, r.ChatAction) {
+		errs = append(errs, fmt.Errorf("unsupported chat action %q", r.ChatAction))
+	}
+	if r.Pause != "" {
+		if d, err := time.ParseDuration(r.Pause); err != nil {
+			errs = append(errs, fmt.Errorf("invalid pause duration %q: %w", r.Pause, err))
+		} else if d <= 0 {
+			errs = append(errs, fmt.Errorf("pause duration %q should be positive", r.Pause))
+		}
+	}
+	return errs
+}
+
+// Action returns chat action of the reply or empty string.
+func (r *Reply) Action() string {
+	if r.Typing {
+		return "typing"
+	}
+	return r.ChatAction
+}
@@ -0,0 +1,186 @@
+package spec
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"unicode/utf8"
+)
+
+var (
+	reCommandName  = regexp.MustCompile(`^[a-z0-9_]{1,32}$`)
+	reLanguageCode = regexp.MustCompile(`^[a-z]{2}$`)
+)
+
+// Command scopes of bot commands menu.
+const (
+	CommandScopeDefault = "default"
+	CommandScopePrivate = "private"
+	CommandScopeGroups  = "groups"
+	CommandScopeAdmins  = "admins"
+)
+
+var commandScopes = []string{CommandScopeDefault, CommandScopePrivate, CommandScopeGroups, CommandScopeAdmins}
+
+// Command is an item of bot commands menu.
+type Command struct {
+	// Command name without slash, e.g. `start`.
+	Command     string `yaml:"command"`
+	Description string `yaml:"description"`
+	// Languages are descriptions for users with language codes, e.g. `ru`.
+	Languages map[string]string `yaml:"languages"`
+	// Scope of the command: `default`, `private`, `groups` or `admins`,
+	// or a list of scopes. Default scope is `default`.
+	Scope Strings `yaml:"scope"`
+}
+
+func (c *Command) scopes() []string {
+	if len(c.Scope) == 0 {
+		return []string{CommandScopeDefault}
+	}
+	return c.Scope
+}
+
+func (c *Command) validate() []error {
+	var errs []error
+	if !reCommandName.MatchString(c.Command) {
+		errs = append(errs, fmt.Errorf("invalid command name %q, use lowercase letters, digits "+
+			"and underscores without slash", c.Command))
+	}
+	if n := utf8.RuneCountInString(c.Description); n < 3 || n > 256 {
+		errs = append(errs, fmt.Errorf("description of command %q should be 3-256 characters", c.Command))
+	}
+	for lang, desc := range c.Languages {
+		if !reLanguageCode.MatchString(lang) {
+			errs = append(errs, fmt.Errorf("invalid language code %q of command %q", lang, c.Command))
+		}
+		if n := utf8.RuneCountInString(desc); n < 3 || n > 256 {
+			errs = append(errs, fmt.Errorf("%s description of command %q should be 3-256 characters", lang, c.Command))
+		}
+	}
+	for _, scope := range c.Scope {
+		if !slices.Contains(commandScopes, scope) {
+			errs = append(errs, fmt.Errorf("invalid scope %q of command %q", scope, c.Command))
+		}
+	}
+	return errs
+}
+
+func validateCommands(cmds []*Command) []error {
+	var errs []error
+	seen := make(map[string]struct{})
+	for _, c := range cmds {
+		errs = append(errs, c.validate()...)
+		for _, scope := range c.scopes() {
+			key := scope + "/" + c.Command
+			if _, ok := seen[key]; ok {
+				errs = append(errs, fmt.Errorf("duplicate command %q in scope %q", c.Command, scope))
+			}
+			seen[key] = struct{}{}
+		}
+	}
+	return errs
+}
+
+// MenuItem is a command with description of commands menu.
+type MenuItem struct {
+	Command     string
+	Description string
+}
+
+// CommandMenu is a list of commands for scope and language,
+// language is empty for default commands of the scope.
+type CommandMenu struct {
+	Scope    string
+	Language string
+	Items    []MenuItem
+}
+
+// CommandMenus returns menus for all scopes and languages of commands.
+// Menu items are empty if scope has no commands or commands have no
+// descriptions of the language, such menus should be deleted to fall
+// back to default language or wider scope.
+func CommandMenus(cmds []*Command) []CommandMenu {
+	langs := []string{""}
+	for _, c := range cmds {
+		for lang := range c.Languages {
+			if !slices.Contains(langs, lang) {
+				langs = append(langs, lang)
+			}
+		}
+	}
+	slices.Sort(langs)
+	var res []CommandMenu
+	for _, scope := range commandScopes {
+		for _, lang := range langs {
+			menu := CommandMenu{Scope: scope, Language: lang}
+			var localized bool
+			for _, c := range cmds {
+				if !slices.Contains(c.scopes(), scope) {
+					continue
+				}
+				desc := c.Description
+				if d, ok := c.Languages[lang]; ok {
+					desc = d
+					localized = true
+				}
+				menu.Items = append(menu.Items, MenuItem{Command: c.Command, Description: desc})
+			}
+			if lang != "" && !localized {
+				menu.Items = nil
+			}
+			res = append(res, menu)
+		}
+	}
+	return res
+}
+
+// ProfileInfo is a name and descriptions of the bot.
+type ProfileInfo struct {
+	// Name of the bot, up to 64 characters.
+	Name string `yaml:"name"`
+	// Description is shown in empty chat with the bot, up to 512 characters.
+	Description string `yaml:"description"`
+	// ShortDescription is shown on the bot profile page, up to 120 characters.
+	ShortDescription string `yaml:"shortDescription"`
+}
+
+func (p *ProfileInfo) validate() []error {
+	var errs []error
+	for _, f := range []struct {
+		name  string
+		value string
+		max   int
+	}{
+		{"name", p.Name, 64},
+		{"description", p.Description, 512},
+		{"short description", p.ShortDescription, 120},
+	} {
+		if n := utf8.RuneCountInString(f.value); n > f.max {
+			errs = append(errs, fmt.Errorf("profile %s is too long: %d > %d", f.name, n, f.max))
+		}
+	}
+	return errs
+}
+
+// Profile of the bot, empty fields are not changed.
+type Profile struct {
+	ProfileInfo `yaml:",inline"`
+	// Languages are profiles for users with language codes, e.g. `ru`.
+	Languages map[string]ProfileInfo `yaml:"languages"`
+}
+
+func (p *Profile) validate() []error {
+	errs := p.ProfileInfo.validate()
+	for lang, info := range p.Languages {
+		if !reLanguageCode.MatchString(lang) {
+			errs = append(errs, fmt.Errorf("invalid profile language code %q", lang))
+		}
+		errs = append(errs, info.validate()...)
+	}
+	if p.ProfileInfo == (ProfileInfo{}) && len(p.Languages) == 0 {
+		errs = append(errs, errors.New("empty profile"))
+	}
+	return errs
+}
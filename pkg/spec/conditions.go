@@ -0,0 +1,43 @@
+package spec
+
+import "errors"
+
+// Comparison is a set of comparison operators for a value.
+// All specified operators should match. Values are compared
+// as numbers if both sides are numbers, and as strings otherwise.
+type Comparison struct {
+	Eq  string `yaml:"eq"`
+	NEq string `yaml:"neq"`
+	Gt  string `yaml:"gt"`
+	Lt  string `yaml:"lt"`
+	Gte string `yaml:"gte"`
+	Lte string `yaml:"lte"`
+	// In matches if value is one of the list.
+	In Strings `yaml:"in"`
+	// Matches is a regular expression to match value.
+	Matches *Pattern `yaml:"matches"`
+}
+
+// Empty checks if comparison has no operators.
+func (c *Comparison) Empty() bool {
+	return c.Eq == "" && c.NEq == "" && c.Gt == "" && c.Lt == "" &&
+		c.Gte == "" && c.Lte == "" && len(c.In) == 0 && c.Matches == nil
+}
+
+// Condition compares interpolated value, e.g. `${data.balance}`
+// or `${message.text}`, using comparison operators.
+type Condition struct {
+	Value      string `yaml:"value"`
+	Comparison `yaml:",inline"`
+}
+
+func (c *Condition) validate() []error {
+	var errs []error
+	if c.Value == "" {
+		errs = append(errs, errors.New("empty condition value"))
+	}
+	if c.Comparison.Empty() {
+		errs = append(errs, errors.New("empty condition comparison"))
+	}
+	return errs
+}
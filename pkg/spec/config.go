@@ -1,5 +1,10 @@
 package spec
 
+import "time"
+
+// DefaultUpdateTimeout is a maximum time to handle one update if not configured.
+const DefaultUpdateTimeout = 3 * time.Second
+
 var DefaultConfig = &Config{
 	Persistence: &PersistenceConfig{
 		Type: MemoryPersistence,
@@ -18,11 +23,27 @@ type Config struct {
 	Assets *AssetsConfig `yaml:"assets"`
 	// PaymentProviders is for payment providers tokens and parameters.
 	PaymentProviders []PaymentProvider `yaml:"paymentProviders"`
+	// AdminChat is a chat ID or `@channel` username, it's a default
+	// target chat of forward and copy replies.
+	AdminChat string `yaml:"adminChat"`
+	// UpdateTimeout is a maximum time to handle one update, e.g. `10s`,
+	// default is 3 seconds.
+	UpdateTimeout string `yaml:"updateTimeout"`
 }
 
 type ApiConfig struct {
 	// Address is the address to listen on.
 	Address string `yaml:"address"`
+	// WebApp enables Mini App endpoints.
+	WebApp *WebAppConfig `yaml:"webApp"`
+}
+
+// WebAppConfig configures Mini App endpoints of API server.
+type WebAppConfig struct {
+	// Assets is an assets key prefix of Mini App static files.
+	Assets string `yaml:"assets"`
+	// MaxAge of Mini App init data, e.g. `1h`, default is `24h`.
+	MaxAge string `yaml:"maxAge"`
 }
 
 type PersistenceType string
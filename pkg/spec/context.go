@@ -1,15 +1,32 @@
 package spec
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"time"
+)
 
 type Context struct {
 	Set    string `yaml:"set"`
 	Delete string `yaml:"delete"`
+	// TTL of the context value, e.g. `15m`. The context is cleared after TTL
+	// and `contextExpired` handlers are triggered.
+	TTL string `yaml:"ttl"`
 }
 
 func (c *Context) validate() []error {
 	if c.Set == "" && c.Delete == "" {
 		return []error{errors.New("empty context")}
 	}
+	if c.TTL != "" {
+		if c.Set == "" {
+			return []error{errors.New("context ttl without set")}
+		}
+		if ttl, err := time.ParseDuration(c.TTL); err != nil {
+			return []error{fmt.Errorf("invalid context ttl %q: %w", c.TTL, err)}
+		} else if ttl <= 0 {
+			return []error{fmt.Errorf("context ttl should be positive: %q", c.TTL)}
+		}
+	}
 	return []error{}
 }
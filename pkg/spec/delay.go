@@ -0,0 +1,40 @@
+package spec
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Delay reply schedules delayed event for the chat, the event triggers
+// handlers with `delayed` trigger. It could also cancel pending events.
+type Delay struct {
+	// Event name to schedule.
+	Event string `yaml:"event"`
+	// After is a duration from now, e.g. `2h` or `30m`.
+	After string `yaml:"after"`
+	// At is a time in RFC3339 format or unix timestamp.
+	At string `yaml:"at"`
+	// Cancel pending events of the chat with this name.
+	Cancel string `yaml:"cancel"`
+}
+
+func (d *Delay) validate() []error {
+	var errs []error
+	if d.Event == "" && d.Cancel == "" {
+		errs = append(errs, errors.New("delay should have event or cancel"))
+	}
+	if d.Event != "" && d.After == "" && d.At == "" {
+		errs = append(errs, errors.New("delay event should have after or at time"))
+	}
+	if d.After != "" && d.At != "" {
+		errs = append(errs, errors.New("delay should have only one of after or at"))
+	}
+	if d.After != "" && !strings.Contains(d.After, "${") {
+		if _, err := time.ParseDuration(d.After); err != nil {
+			errs = append(errs, fmt.Errorf("invalid delay duration %q: %w", d.After, err))
+		}
+	}
+	return errs
+}
@@ -0,0 +1,56 @@
+package spec
+
+import (
+	"errors"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Forward reply forwards or copies the message of the update to another chat.
+// It could be declared as a target chat or `true` to use admin chat of config.
+type Forward struct {
+	// To is a target chat ID or `@channel` username, it's interpolated,
+	// e.g. `${state.operator_chat}`. Default is `adminChat` of config.
+	To string `yaml:"to"`
+	// Caption overrides caption of copied message, it's interpolated.
+	Caption   string    `yaml:"caption"`
+	ParseMode ParseMode `yaml:"parseMode"`
+}
+
+func (f *Forward) UnmarshalYAML(node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		if node.Value != "true" {
+			f.To = node.Value
+		}
+	case yaml.AliasNode:
+		return f.UnmarshalYAML(node.Alias)
+	case yaml.MappingNode:
+		var schema struct {
+			To        string    `yaml:"to"`
+			Caption   string    `yaml:"caption"`
+			ParseMode ParseMode `yaml:"parseMode"`
+		}
+		if err := node.Decode(&schema); err != nil {
+			return err
+		}
+		f.To = schema.To
+		f.Caption = schema.Caption
+		f.ParseMode = schema.ParseMode
+	default:
+		return fmt.Errorf("unexpected node kind: %v", node.Kind)
+	}
+	return nil
+}
+
+func (f *Forward) validate() []error {
+	var errs []error
+	if f.ParseMode != "" {
+		errs = append(errs, f.ParseMode.validate()...)
+	}
+	if f.ParseMode != "" && f.Caption == "" {
+		errs = append(errs, errors.New("parse mode without caption"))
+	}
+	return errs
+}
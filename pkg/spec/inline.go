@@ -0,0 +1,75 @@
+package spec
+
+import (
+	"errors"
+	"fmt"
+)
+
+// InlineResults is a reply to inline query.
+type InlineResults struct {
+	// Articles is a static list of results.
+	Articles []*InlineArticle `yaml:"articles"`
+	// Foreach is a path to array in loaded data, e.g. `data` or `data.items`,
+	// each item of the array is rendered as an Article.
+	Foreach string `yaml:"foreach"`
+	// Article is a template of a result for each item of Foreach array.
+	Article *InlineArticle `yaml:"article"`
+	// CacheTime is a maximum amount of time in seconds the results may be cached on the server.
+	CacheTime int `yaml:"cacheTime"`
+	// Personal results are cached only for the user that sent the query.
+	Personal bool `yaml:"personal"`
+}
+
+func (r *InlineResults) validate() []error {
+	var errs []error
+	if len(r.Articles) == 0 && r.Foreach == "" {
+		errs = append(errs, errors.New("empty inline results"))
+	}
+	if len(r.Articles) > 0 && r.Foreach != "" {
+		errs = append(errs, errors.New("both inline articles and foreach are set"))
+	}
+	if r.Foreach != "" && r.Article == nil {
+		errs = append(errs, errors.New("inline results foreach without article"))
+	}
+	for _, a := range r.Articles {
+		errs = append(errs, a.validate()...)
+	}
+	if r.Article != nil {
+		errs = append(errs, r.Article.validate()...)
+	}
+	return errs
+}
+
+// InlineArticle is an inline query result article.
+type InlineArticle struct {
+	// ID of the result, index of the result is used if empty.
+	ID          string `yaml:"id"`
+	Title       string `yaml:"title"`
+	Description string `yaml:"description"`
+	ThumbURL    string `yaml:"thumbUrl"`
+	// Text of the message to be sent.
+	Text           string           `yaml:"text"`
+	ParseMode      ParseMode        `yaml:"parseMode"`
+	InlineKeyboard [][]InlineButton `yaml:"inlineKeyboard"`
+}
+
+func (a *InlineArticle) validate() []error {
+	var errs []error
+	if a.Title == "" {
+		errs = append(errs, errors.New("empty inline article title"))
+	}
+	if a.Text == "" {
+		errs = append(errs, errors.New("empty inline article text"))
+	}
+	if a.ParseMode != "" {
+		errs = append(errs, a.ParseMode.validate()...)
+	}
+	for i, row := range a.InlineKeyboard {
+		for j, btn := range row {
+			if btn.Text == "" {
+				errs = append(errs, fmt.Errorf("empty inline article button %d:%d", i, j))
+			}
+		}
+	}
+	return errs
+}
@@ -0,0 +1,79 @@
+package spec
+
+import (
+	"errors"
+	"fmt"
+)
+
+// RequestUsers is a criteria of users to share by keyboard button,
+// shared users are handled by `shared` message trigger.
+type RequestUsers struct {
+	// ID of the request, it's sent back with shared users.
+	ID int `yaml:"id"`
+	// Bot requests bots if true or regular users if false, any if not set.
+	Bot *bool `yaml:"bot"`
+	// Premium requests premium users if true or non-premium if false, any if not set.
+	Premium *bool `yaml:"premium"`
+	// Max is a maximum number of users to select, from 1 to 10, default is 1.
+	Max int `yaml:"max"`
+}
+
+func (r *RequestUsers) validate() []error {
+	if r.Max < 0 || r.Max > 10 {
+		return []error{fmt.Errorf("invalid request users max %d", r.Max)}
+	}
+	return nil
+}
+
+// RequestChat is a criteria of a chat to share by keyboard button,
+// shared chat is handled by `shared` message trigger.
+type RequestChat struct {
+	// ID of the request, it's sent back with shared chat.
+	ID int `yaml:"id"`
+	// Channel requests channel if true or group if false.
+	Channel bool `yaml:"channel"`
+	// Forum requests forum supergroup if true or non-forum if false, any if not set.
+	Forum *bool `yaml:"forum"`
+	// Username requests chat with username if true or without if false, any if not set.
+	Username *bool `yaml:"username"`
+	// Created requests chat owned by the user.
+	Created bool `yaml:"created"`
+	// BotMember requests chat with the bot as a member.
+	BotMember bool `yaml:"botMember"`
+}
+
+func (b *KeyboardButton) validate() []error {
+	var errs []error
+	var requests int
+	for _, ok := range []bool{
+		b.WebApp != "", b.RequestContact, b.RequestLocation, b.RequestPoll != "",
+		b.RequestUsers != nil, b.RequestChat != nil,
+	} {
+		if ok {
+			requests++
+		}
+	}
+	if requests > 1 {
+		errs = append(errs, errors.New("multiple actions of keyboard button"))
+	}
+	switch b.RequestPoll {
+	case "", "any", "quiz", "regular":
+	default:
+		errs = append(errs, fmt.Errorf("invalid request poll type %q", b.RequestPoll))
+	}
+	if b.RequestUsers != nil {
+		errs = append(errs, b.RequestUsers.validate()...)
+	}
+	return errs
+}
+
+// requestID returns ID of users or chat request.
+func (b *KeyboardButton) requestID() (int, bool) {
+	switch {
+	case b.RequestUsers != nil:
+		return b.RequestUsers.ID, true
+	case b.RequestChat != nil:
+		return b.RequestChat.ID, true
+	}
+	return 0, false
+}
@@ -0,0 +1,144 @@
+package spec
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Media types of media group item.
+const (
+	MediaPhoto = "photo"
+	MediaVideo = "video"
+)
+
+// MediaItem is a photo or video of media group.
+type MediaItem struct {
+	// Type of media, `photo` or `video`.
+	Type      string `yaml:"type"`
+	FileReply `yaml:",inline"`
+}
+
+func validateMediaGroup(items []*MediaItem) []error {
+	var errs []error
+	if len(items) < 2 || len(items) > 10 {
+		errs = append(errs, fmt.Errorf("media group should have 2-10 items, got %d", len(items)))
+	}
+	for i, item := range items {
+		if item.Type != MediaPhoto && item.Type != MediaVideo {
+			errs = append(errs, fmt.Errorf("invalid media group item %d type %q", i, item.Type))
+		}
+		for _, err := range item.FileReply.validate() {
+			errs = append(errs, fmt.Errorf("media group item %d: %w", i, err))
+		}
+	}
+	return errs
+}
+
+// Location reply sends a point on the map, coordinates are interpolated.
+type Location struct {
+	Latitude  string `yaml:"latitude"`
+	Longitude string `yaml:"longitude"`
+}
+
+func (l *Location) validate() []error {
+	var errs []error
+	if err := validateCoordinate("latitude", l.Latitude, 90); err != nil {
+		errs = append(errs, err)
+	}
+	if err := validateCoordinate("longitude", l.Longitude, 180); err != nil {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+func validateCoordinate(name, val string, limit float64) error {
+	if val == "" {
+		return fmt.Errorf("empty %s", name)
+	}
+	if strings.Contains(val, "${") {
+		return nil
+	}
+	f, err := strconv.ParseFloat(val, 64)
+	if err != nil || f < -limit || f > limit {
+		return fmt.Errorf("invalid %s %q", name, val)
+	}
+	return nil
+}
+
+// Venue reply sends a location with title and address.
+type Venue struct {
+	Location `yaml:",inline"`
+	Title    string `yaml:"title"`
+	Address  string `yaml:"address"`
+}
+
+func (v *Venue) validate() []error {
+	errs := v.Location.validate()
+	if v.Title == "" {
+		errs = append(errs, errors.New("empty venue title"))
+	}
+	if v.Address == "" {
+		errs = append(errs, errors.New("empty venue address"))
+	}
+	return errs
+}
+
+// Contact reply sends a phone contact.
+type Contact struct {
+	PhoneNumber string `yaml:"phoneNumber"`
+	FirstName   string `yaml:"firstName"`
+	LastName    string `yaml:"lastName"`
+}
+
+func (c *Contact) validate() []error {
+	var errs []error
+	if c.PhoneNumber == "" {
+		errs = append(errs, errors.New("empty contact phone number"))
+	}
+	if c.FirstName == "" {
+		errs = append(errs, errors.New("empty contact first name"))
+	}
+	return errs
+}
+
+var diceEmoji = []string{"🎲", "🎯", "🏀", "⚽", "🎳", "🎰"}
+
+// Dice reply sends animated emoji with random value. It could be declared
+// as an emoji or `true` for default dice.
+type Dice struct {
+	Emoji string `yaml:"emoji"`
+}
+
+func (d *Dice) UnmarshalYAML(node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		if node.Value != "true" {
+			d.Emoji = node.Value
+		}
+	case yaml.AliasNode:
+		return d.UnmarshalYAML(node.Alias)
+	case yaml.MappingNode:
+		var schema struct {
+			Emoji string `yaml:"emoji"`
+		}
+		if err := node.Decode(&schema); err != nil {
+			return err
+		}
+		d.Emoji = schema.Emoji
+	default:
+		return fmt.Errorf("unexpected node kind: %v", node.Kind)
+	}
+	return nil
+}
+
+func (d *Dice) validate() []error {
+	if d.Emoji != "" && !slices.Contains(diceEmoji, d.Emoji) {
+		return []error{fmt.Errorf("unsupported dice emoji %q", d.Emoji)}
+	}
+	return nil
+}
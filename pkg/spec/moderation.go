@@ -0,0 +1,236 @@
+package spec
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Target of moderation action.
+type Target string
+
+const (
+	// TargetSender is a sender of the update, or the update message
+	// for message actions. It's a default target.
+	TargetSender = Target("sender")
+	// TargetReply is a message replied by the update message, or its sender.
+	TargetReply = Target("reply")
+)
+
+func (t Target) validate() []error {
+	switch t {
+	case "", TargetSender, TargetReply:
+		return nil
+	}
+	return []error{fmt.Errorf("invalid moderation target %q", t)}
+}
+
+// decodeAction decodes moderation action which could be declared as `true`
+// for default parameters, or `false` to disable the action.
+func decodeAction(node *yaml.Node, v any, disabled *bool) error {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		var enabled bool
+		if err := node.Decode(&enabled); err != nil {
+			return fmt.Errorf("unexpected action value: %q", node.Value)
+		}
+		*disabled = !enabled
+		return nil
+	case yaml.AliasNode:
+		return decodeAction(node.Alias, v, disabled)
+	case yaml.MappingNode:
+		return node.Decode(v)
+	}
+	return fmt.Errorf("unexpected node kind: %v", node.Kind)
+}
+
+// Delete reply deletes the message, it's a callback message for callback updates.
+type Delete struct {
+	Target   Target `yaml:"target"`
+	disabled bool
+}
+
+func (d *Delete) UnmarshalYAML(node *yaml.Node) error {
+	type plain Delete
+	return decodeAction(node, (*plain)(d), &d.disabled)
+}
+
+// Pin reply pins the message in the chat.
+type Pin struct {
+	Target Target `yaml:"target"`
+	// Silent pins message without notification of chat members.
+	Silent   bool `yaml:"silent"`
+	disabled bool
+}
+
+func (p *Pin) UnmarshalYAML(node *yaml.Node) error {
+	type plain Pin
+	return decodeAction(node, (*plain)(p), &p.disabled)
+}
+
+// Unpin reply unpins the message or all messages of the chat.
+type Unpin struct {
+	Target Target `yaml:"target"`
+	// All unpins all pinned messages of the chat.
+	All      bool `yaml:"all"`
+	disabled bool
+}
+
+func (u *Unpin) UnmarshalYAML(node *yaml.Node) error {
+	type plain Unpin
+	return decodeAction(node, (*plain)(u), &u.disabled)
+}
+
+// Ban reply bans the user in the chat.
+type Ban struct {
+	Target Target `yaml:"target"`
+	// Until is a duration from now, e.g. `24h`, RFC3339 time or unix timestamp,
+	// it's interpolated. The user is banned forever if empty.
+	Until string `yaml:"until"`
+	// RevokeMessages deletes all messages of the user in the chat.
+	RevokeMessages bool `yaml:"revokeMessages"`
+	disabled       bool
+}
+
+func (b *Ban) UnmarshalYAML(node *yaml.Node) error {
+	type plain Ban
+	return decodeAction(node, (*plain)(b), &b.disabled)
+}
+
+// Unban reply unbans the user in the chat.
+type Unban struct {
+	Target   Target `yaml:"target"`
+	disabled bool
+}
+
+func (u *Unban) UnmarshalYAML(node *yaml.Node) error {
+	type plain Unban
+	return decodeAction(node, (*plain)(u), &u.disabled)
+}
+
+// Restrict reply restricts permissions of the user in the chat.
+type Restrict struct {
+	Target Target `yaml:"target"`
+	// Until is a duration from now, e.g. `1h`, RFC3339 time or unix timestamp,
+	// it's interpolated. The user is restricted forever if empty.
+	Until string `yaml:"until"`
+	// Permissions allowed to the user, all are denied by default.
+	Permissions ChatPermissions `yaml:"permissions"`
+	disabled    bool
+}
+
+func (r *Restrict) UnmarshalYAML(node *yaml.Node) error {
+	type plain Restrict
+	return decodeAction(node, (*plain)(r), &r.disabled)
+}
+
+// ChatPermissions of restricted user.
+type ChatPermissions struct {
+	SendMessages       bool `yaml:"sendMessages"`
+	SendMedia          bool `yaml:"sendMedia"`
+	SendPolls          bool `yaml:"sendPolls"`
+	SendOther          bool `yaml:"sendOther"`
+	AddWebPagePreviews bool `yaml:"addWebPagePreviews"`
+	ChangeInfo         bool `yaml:"changeInfo"`
+	InviteUsers        bool `yaml:"inviteUsers"`
+	PinMessages        bool `yaml:"pinMessages"`
+}
+
+// Promote reply promotes the user to chat administrator with rights.
+type Promote struct {
+	Target           Target `yaml:"target"`
+	Anonymous        bool   `yaml:"anonymous"`
+	ManageChat       bool   `yaml:"manageChat"`
+	ChangeInfo       bool   `yaml:"changeInfo"`
+	PostMessages     bool   `yaml:"postMessages"`
+	EditMessages     bool   `yaml:"editMessages"`
+	DeleteMessages   bool   `yaml:"deleteMessages"`
+	ManageVideoChats bool   `yaml:"manageVideoChats"`
+	InviteUsers      bool   `yaml:"inviteUsers"`
+	RestrictMembers  bool   `yaml:"restrictMembers"`
+	PinMessages      bool   `yaml:"pinMessages"`
+	PromoteMembers   bool   `yaml:"promoteMembers"`
+	disabled         bool
+}
+
+func (p *Promote) UnmarshalYAML(node *yaml.Node) error {
+	type plain Promote
+	return decodeAction(node, (*plain)(p), &p.disabled)
+}
+
+// dropDisabledActions removes actions declared as `false`.
+func (r *Reply) dropDisabledActions() {
+	if r.Delete != nil && r.Delete.disabled {
+		r.Delete = nil
+	}
+	if r.Pin != nil && r.Pin.disabled {
+		r.Pin = nil
+	}
+	if r.Unpin != nil && r.Unpin.disabled {
+		r.Unpin = nil
+	}
+	if r.Ban != nil && r.Ban.disabled {
+		r.Ban = nil
+	}
+	if r.Unban != nil && r.Unban.disabled {
+		r.Unban = nil
+	}
+	if r.Restrict != nil && r.Restrict.disabled {
+		r.Restrict = nil
+	}
+	if r.Promote != nil && r.Promote.disabled {
+		r.Promote = nil
+	}
+}
+
+// validateUntil checks until time if it's not interpolated.
+func validateUntil(until string) []error {
+	if until == "" || strings.Contains(until, "${") {
+		return nil
+	}
+	if _, err := time.ParseDuration(until); err == nil {
+		return nil
+	}
+	if _, err := strconv.ParseInt(until, 10, 64); err == nil {
+		return nil
+	}
+	if _, err := time.Parse(time.RFC3339, until); err == nil {
+		return nil
+	}
+	return []error{fmt.Errorf("invalid until time %q", until)}
+}
+
+func (r *Reply) validateModeration() []error {
+	var errs []error
+	if r.Delete != nil {
+		errs = append(errs, r.Delete.Target.validate()...)
+	}
+	if r.Pin != nil {
+		errs = append(errs, r.Pin.Target.validate()...)
+	}
+	if r.Unpin != nil {
+		errs = append(errs, r.Unpin.Target.validate()...)
+		if r.Unpin.All && r.Unpin.Target != "" {
+			errs = append(errs, errors.New("unpin all with target"))
+		}
+	}
+	if r.Ban != nil {
+		errs = append(errs, r.Ban.Target.validate()...)
+		errs = append(errs, validateUntil(r.Ban.Until)...)
+	}
+	if r.Unban != nil {
+		errs = append(errs, r.Unban.Target.validate()...)
+	}
+	if r.Restrict != nil {
+		errs = append(errs, r.Restrict.Target.validate()...)
+		errs = append(errs, validateUntil(r.Restrict.Until)...)
+	}
+	if r.Promote != nil {
+		errs = append(errs, r.Promote.Target.validate()...)
+	}
+	return errs
+}
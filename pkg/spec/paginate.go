@@ -0,0 +1,53 @@
+package spec
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var rePaginateName = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+// Paginate reply sends a page of items with previous and next buttons,
+// the message is edited in place when these buttons are pressed.
+type Paginate struct {
+	// Name of the list, it should be unique, it's used in callback data of buttons.
+	Name string `yaml:"name"`
+	// Foreach is a path to array, e.g. `data`, `data.items` or `state.<key>`.
+	Foreach string `yaml:"foreach"`
+	// PageSize is a number of items per page, default is 5.
+	PageSize int `yaml:"pageSize"`
+	// Header is a text before items.
+	Header string `yaml:"header"`
+	// Item is a template of the line for each item.
+	Item string `yaml:"item"`
+	// Footer is a text after items.
+	Footer string `yaml:"footer"`
+	// Empty is a text of message without items.
+	Empty string `yaml:"empty"`
+	// Prev and Next are texts of buttons.
+	Prev      string    `yaml:"prev"`
+	Next      string    `yaml:"next"`
+	ParseMode ParseMode `yaml:"parseMode"`
+}
+
+func (p *Paginate) validate() []error {
+	var errs []error
+	if !rePaginateName.MatchString(p.Name) {
+		errs = append(errs, fmt.Errorf("invalid paginate name %q", p.Name))
+	}
+	if p.Foreach != "data" && !strings.HasPrefix(p.Foreach, "data.") && !strings.HasPrefix(p.Foreach, "state.") {
+		errs = append(errs, fmt.Errorf("invalid paginate foreach path %q", p.Foreach))
+	}
+	if p.Item == "" {
+		errs = append(errs, errors.New("empty paginate item"))
+	}
+	if p.PageSize < 0 {
+		errs = append(errs, fmt.Errorf("invalid paginate page size %d", p.PageSize))
+	}
+	if p.ParseMode != "" {
+		errs = append(errs, p.ParseMode.validate()...)
+	}
+	return errs
+}
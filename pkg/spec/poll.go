@@ -0,0 +1,105 @@
+package spec
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Poll reply sends regular poll or quiz to the chat.
+// Question, options and correct option are interpolated.
+type Poll struct {
+	// Name of the poll, it's used by `pollAnswer` trigger
+	// to handle answers of this poll.
+	Name     string `yaml:"name"`
+	Question string `yaml:"question"`
+	// Options is a static list of poll options.
+	Options Strings `yaml:"options"`
+	// Foreach is a path to array in loaded data, e.g. `data` or `data.items`,
+	// each item of the array is rendered as an Option.
+	Foreach string `yaml:"foreach"`
+	// Option is a template of an option for each item of Foreach array.
+	Option string `yaml:"option"`
+	// Quiz poll has correct option.
+	Quiz bool `yaml:"quiz"`
+	// CorrectOption is an index of correct option of quiz.
+	CorrectOption string `yaml:"correctOption"`
+	// Explanation is shown when user chooses incorrect answer of quiz.
+	Explanation string `yaml:"explanation"`
+	// Anonymous polls don't send answers to the bot.
+	Anonymous       bool `yaml:"anonymous"`
+	MultipleAnswers bool `yaml:"multipleAnswers"`
+	// OpenPeriod is a time in seconds the poll will be active after creation.
+	OpenPeriod int `yaml:"openPeriod"`
+}
+
+func (p *Poll) validate() []error {
+	var errs []error
+	if p.Question == "" {
+		errs = append(errs, errors.New("empty poll question"))
+	}
+	if len(p.Options) == 0 && p.Foreach == "" {
+		errs = append(errs, errors.New("empty poll options"))
+	}
+	if len(p.Options) > 0 && p.Foreach != "" {
+		errs = append(errs, errors.New("both poll options and foreach are set"))
+	}
+	if p.Foreach != "" && p.Option == "" {
+		errs = append(errs, errors.New("poll foreach without option"))
+	}
+	if p.Quiz && p.CorrectOption == "" {
+		errs = append(errs, errors.New("quiz without correct option"))
+	}
+	if !p.Quiz && (p.CorrectOption != "" || p.Explanation != "") {
+		errs = append(errs, errors.New("correct option or explanation of regular poll"))
+	}
+	if p.Quiz && p.MultipleAnswers {
+		errs = append(errs, errors.New("quiz with multiple answers"))
+	}
+	if p.CorrectOption != "" && !strings.Contains(p.CorrectOption, "${") {
+		if i, err := strconv.Atoi(p.CorrectOption); err != nil || i < 0 ||
+			(len(p.Options) > 0 && i >= len(p.Options)) {
+			errs = append(errs, fmt.Errorf("invalid quiz correct option %q", p.CorrectOption))
+		}
+	}
+	if p.Anonymous && p.Name != "" {
+		errs = append(errs, errors.New("anonymous poll answers could not be handled"))
+	}
+	return errs
+}
+
+// PollAnswerTrigger matches answers of polls sent by the bot.
+// It could be declared as a poll name or `true` to match any poll.
+type PollAnswerTrigger struct {
+	// Poll name, any poll if empty.
+	Poll string `yaml:"poll"`
+	// Correct matches correct or incorrect answers of quiz.
+	Correct OptBool `yaml:"correct"`
+}
+
+func (t *PollAnswerTrigger) UnmarshalYAML(node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		if node.Value != "true" {
+			t.Poll = node.Value
+		}
+	case yaml.AliasNode:
+		return t.UnmarshalYAML(node.Alias)
+	case yaml.MappingNode:
+		var schema struct {
+			Poll    string  `yaml:"poll"`
+			Correct OptBool `yaml:"correct"`
+		}
+		if err := node.Decode(&schema); err != nil {
+			return err
+		}
+		t.Poll = schema.Poll
+		t.Correct = schema.Correct
+	default:
+		return fmt.Errorf("unexpected node kind: %v", node.Kind)
+	}
+	return nil
+}
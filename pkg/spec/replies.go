@@ -3,6 +3,8 @@ package spec
 import (
 	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 
 	"gopkg.in/yaml.v3"
 )
@@ -11,17 +13,96 @@ type Reply struct {
 	Message     *MessageReply      `yaml:"message"`
 	Callback    *CallbackReply     `yaml:"callback"`
 	Edit        *Edit              `yaml:"edit"`
-	Delete      bool               `yaml:"delete"`
+	Delete      *Delete            `yaml:"delete"`
 	Image       *FileReply         `yaml:"image"`
 	Document    *FileReply         `yaml:"document"`
 	Invoice     *Invoice           `yaml:"invoice"`
 	PreCheckout *PreCheckoutAnswer `yaml:"preCheckout"`
+	// InlineResults answers inline query.
+	InlineResults *InlineResults `yaml:"inlineResults"`
+	// ApproveJoinRequest approves chat join request of the update.
+	ApproveJoinRequest bool `yaml:"approveJoinRequest"`
+	// DeclineJoinRequest declines chat join request of the update.
+	DeclineJoinRequest bool `yaml:"declineJoinRequest"`
+	// Delay schedules or cancels delayed event.
+	Delay *Delay `yaml:"delay"`
+	// Poll sends poll or quiz.
+	Poll *Poll `yaml:"poll"`
+	// Paginate sends paginated list.
+	Paginate *Paginate `yaml:"paginate"`
+	// Audio, Voice, Video, Animation and Sticker send media files.
+	Audio     *FileReply `yaml:"audio"`
+	Voice     *FileReply `yaml:"voice"`
+	Video     *FileReply `yaml:"video"`
+	Animation *FileReply `yaml:"animation"`
+	Sticker   *FileReply `yaml:"sticker"`
+	// MediaGroup sends an album of photos and videos.
+	MediaGroup []*MediaItem `yaml:"mediaGroup"`
+	Location   *Location    `yaml:"location"`
+	Venue      *Venue       `yaml:"venue"`
+	Contact    *Contact     `yaml:"contact"`
+	// Dice sends animated emoji with random value.
+	Dice *Dice `yaml:"dice"`
+	// Forward forwards the message of the update to another chat.
+	Forward *Forward `yaml:"forward"`
+	// Copy copies the message of the update to another chat without link to the original message.
+	Copy *Forward `yaml:"copy"`
+	// Pin and Unpin pin or unpin the message in the chat.
+	Pin   *Pin   `yaml:"pin"`
+	Unpin *Unpin `yaml:"unpin"`
+	// Ban, Unban, Restrict and Promote manage the chat member.
+	Ban      *Ban      `yaml:"ban"`
+	Unban    *Unban    `yaml:"unban"`
+	Restrict *Restrict `yaml:"restrict"`
+	Promote  *Promote  `yaml:"promote"`
+	// SetChatTitle changes title of the chat, it's interpolated.
+	SetChatTitle string `yaml:"setChatTitle"`
+	// Typing sends `typing` chat action before other replies of the item.
+	Typing bool `yaml:"typing"`
+	// ChatAction sends chat action, e.g. `upload_photo`, before other replies of the item.
+	ChatAction string `yaml:"chatAction"`
+	// Pause waits before the next replies, e.g. `1500ms`.
+	Pause string `yaml:"pause"`
+
+	// SendOptions are applied to messages sent by this reply.
+	SendOptions `yaml:",inline"`
+}
+
+func (r *Reply) UnmarshalYAML(node *yaml.Node) error {
+	type plain Reply
+	if err := node.Decode((*plain)(r)); err != nil {
+		return err
+	}
+	r.dropDisabledActions()
+	return nil
+}
+
+// SendOptions are common options of sent messages.
+type SendOptions struct {
+	// ReplyTo sends message as a reply to the update message.
+	ReplyTo bool `yaml:"replyTo"`
+	// DisableNotification sends message silently.
+	DisableNotification bool `yaml:"disableNotification"`
+	// ProtectContent protects message from forwarding and saving.
+	ProtectContent bool `yaml:"protectContent"`
+	// DisableLinkPreview disables link previews of text message.
+	DisableLinkPreview bool `yaml:"disableLinkPreview"`
+	// ThreadID is a forum topic ID to send message, it's interpolated.
+	// Default is a topic of the update message.
+	ThreadID string `yaml:"threadId"`
 }
 
 func (r *Reply) validate() (errs []error) {
 	errs = make([]error, 0)
-	if r.Message == nil && r.Callback == nil && r.Edit == nil && !r.Delete &&
-		r.Image == nil && r.Document == nil && r.Invoice == nil && r.PreCheckout == nil {
+	if r.Message == nil && r.Callback == nil && r.Edit == nil && r.Delete == nil &&
+		r.Image == nil && r.Document == nil && r.Invoice == nil && r.PreCheckout == nil &&
+		r.InlineResults == nil && !r.ApproveJoinRequest && !r.DeclineJoinRequest &&
+		r.Delay == nil && r.Poll == nil && r.Paginate == nil &&
+		r.Audio == nil && r.Voice == nil && r.Video == nil && r.Animation == nil && r.Sticker == nil &&
+		len(r.MediaGroup) == 0 && r.Location == nil && r.Venue == nil && r.Contact == nil && r.Dice == nil &&
+		r.Forward == nil && r.Copy == nil && r.Pin == nil && r.Unpin == nil &&
+		r.Ban == nil && r.Unban == nil && r.Restrict == nil && r.Promote == nil && r.SetChatTitle == "" &&
+		!r.Typing && r.ChatAction == "" && r.Pause == "" {
 		errs = append(errs, errors.New("empty reply"))
 	}
 	if r.Message != nil {
@@ -42,6 +123,63 @@ func (r *Reply) validate() (errs []error) {
 	if r.Invoice != nil {
 		errs = append(errs, r.Invoice.validate()...)
 	}
+	if r.InlineResults != nil {
+		errs = append(errs, r.InlineResults.validate()...)
+	}
+	if r.Delay != nil {
+		errs = append(errs, r.Delay.validate()...)
+	}
+	if r.Poll != nil {
+		errs = append(errs, r.Poll.validate()...)
+	}
+	if r.Paginate != nil {
+		errs = append(errs, r.Paginate.validate()...)
+	}
+	for _, f := range []*FileReply{r.Audio, r.Voice, r.Video, r.Animation} {
+		if f != nil {
+			errs = append(errs, f.validate()...)
+		}
+	}
+	if r.Sticker != nil {
+		errs = append(errs, r.Sticker.validate()...)
+		if r.Sticker.Caption != "" {
+			errs = append(errs, errors.New("sticker with caption"))
+		}
+	}
+	if r.MediaGroup != nil {
+		errs = append(errs, validateMediaGroup(r.MediaGroup)...)
+	}
+	if r.Location != nil {
+		errs = append(errs, r.Location.validate()...)
+	}
+	if r.Venue != nil {
+		errs = append(errs, r.Venue.validate()...)
+	}
+	if r.Contact != nil {
+		errs = append(errs, r.Contact.validate()...)
+	}
+	if r.Dice != nil {
+		errs = append(errs, r.Dice.validate()...)
+	}
+	if r.Forward != nil {
+		errs = append(errs, r.Forward.validate()...)
+		if r.Forward.Caption != "" {
+			errs = append(errs, errors.New("forward with caption, use copy instead"))
+		}
+	}
+	if r.Copy != nil {
+		errs = append(errs, r.Copy.validate()...)
+	}
+	errs = append(errs, r.validatePacing()...)
+	errs = append(errs, r.validateModeration()...)
+	if id := r.ThreadID; id != "" && !strings.Contains(id, "${") {
+		if n, err := strconv.Atoi(id); err != nil || n <= 0 {
+			errs = append(errs, fmt.Errorf("invalid thread ID %q", id))
+		}
+	}
+	if r.ApproveJoinRequest && r.DeclineJoinRequest {
+		errs = append(errs, errors.New("both approve and decline join request"))
+	}
 	return
 }
 
@@ -138,26 +276,204 @@ type InlineButton struct {
 	Text     string `yaml:"text"`
 	URL      string `yaml:"url"`
 	Callback string `yaml:"callback"`
+	// WebApp is a URL of Mini App to open.
+	WebApp string `yaml:"webApp"`
+}
+
+// KeyboardButton is a button of chat keyboard, it could be
+// declared as a string for text button.
+type KeyboardButton struct {
+	Text string `yaml:"text"`
+	// WebApp is a URL of Mini App to open, it's available only in private chats.
+	WebApp string `yaml:"webApp"`
+	// RequestContact sends user's phone number as a contact.
+	RequestContact bool `yaml:"requestContact"`
+	// RequestLocation sends user's current location.
+	RequestLocation bool `yaml:"requestLocation"`
+	// RequestPoll asks user to create a poll: `any`, `quiz` or `regular`.
+	RequestPoll string `yaml:"requestPoll"`
+	// RequestUsers asks user to select users to share with the bot.
+	RequestUsers *RequestUsers `yaml:"requestUsers"`
+	// RequestChat asks user to select a chat to share with the bot.
+	RequestChat *RequestChat `yaml:"requestChat"`
+}
+
+func (b *KeyboardButton) UnmarshalYAML(node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		b.Text = node.Value
+	case yaml.AliasNode:
+		return b.UnmarshalYAML(node.Alias)
+	case yaml.MappingNode:
+		var schema struct {
+			Text            string        `yaml:"text"`
+			WebApp          string        `yaml:"webApp"`
+			RequestContact  bool          `yaml:"requestContact"`
+			RequestLocation bool          `yaml:"requestLocation"`
+			RequestPoll     string        `yaml:"requestPoll"`
+			RequestUsers    *RequestUsers `yaml:"requestUsers"`
+			RequestChat     *RequestChat  `yaml:"requestChat"`
+		}
+		if err := node.Decode(&schema); err != nil {
+			return err
+		}
+		b.Text = schema.Text
+		b.WebApp = schema.WebApp
+		b.RequestContact = schema.RequestContact
+		b.RequestLocation = schema.RequestLocation
+		b.RequestPoll = schema.RequestPoll
+		b.RequestUsers = schema.RequestUsers
+		b.RequestChat = schema.RequestChat
+	default:
+		return fmt.Errorf("unexpected node kind: %v", node.Kind)
+	}
+	return nil
 }
 
 type ReplyMarkup struct {
-	Keyboard       [][]string       `yaml:"keyboard"`
-	InlineKeyboard [][]InlineButton `yaml:"inlineKeyboard"`
+	Keyboard       [][]KeyboardButton `yaml:"keyboard"`
+	InlineKeyboard [][]InlineButton   `yaml:"inlineKeyboard"`
+	// InlineForeach is a dynamic inline keyboard, it's declared
+	// as an object of `inlineKeyboard` field.
+	InlineForeach *InlineKeyboardForeach `yaml:"-"`
+	// RemoveKeyboard removes chat keyboard.
+	RemoveKeyboard bool `yaml:"removeKeyboard"`
+	// ForceReply shows reply interface to the user.
+	ForceReply bool `yaml:"forceReply"`
+	// OneTime hides chat keyboard after it's used.
+	OneTime bool `yaml:"oneTime"`
+	// Resize fits chat keyboard height to its buttons, it's true by default.
+	Resize OptBool `yaml:"resize"`
+	// Placeholder of input field when chat keyboard or reply interface is active, it's interpolated.
+	Placeholder string `yaml:"placeholder"`
+	// Selective shows chat keyboard or reply interface only to mentioned users
+	// and sender of the replied message.
+	Selective bool `yaml:"selective"`
+}
+
+// ResizeKeyboard checks if chat keyboard should be resized, it's true if not set.
+func (r *ReplyMarkup) ResizeKeyboard() bool {
+	return !r.Resize.Valid || r.Resize.Value
+}
+
+func (r *ReplyMarkup) UnmarshalYAML(node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.AliasNode:
+		return r.UnmarshalYAML(node.Alias)
+	case yaml.MappingNode:
+		schema := &struct {
+			Keyboard       [][]KeyboardButton `yaml:"keyboard"`
+			InlineKeyboard yaml.Node          `yaml:"inlineKeyboard"`
+			RemoveKeyboard bool               `yaml:"removeKeyboard"`
+			ForceReply     bool               `yaml:"forceReply"`
+			OneTime        bool               `yaml:"oneTime"`
+			Resize         OptBool            `yaml:"resize"`
+			Placeholder    string             `yaml:"placeholder"`
+			Selective      bool               `yaml:"selective"`
+		}{}
+		if err := node.Decode(schema); err != nil {
+			return err
+		}
+		r.Keyboard = schema.Keyboard
+		r.RemoveKeyboard = schema.RemoveKeyboard
+		r.ForceReply = schema.ForceReply
+		r.OneTime = schema.OneTime
+		r.Resize = schema.Resize
+		r.Placeholder = schema.Placeholder
+		r.Selective = schema.Selective
+		inline := &schema.InlineKeyboard
+		if inline.Kind == yaml.AliasNode {
+			inline = inline.Alias
+		}
+		switch inline.Kind {
+		case 0:
+		case yaml.MappingNode:
+			r.InlineForeach = new(InlineKeyboardForeach)
+			if err := inline.Decode(r.InlineForeach); err != nil {
+				return err
+			}
+		default:
+			if err := inline.Decode(&r.InlineKeyboard); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("unexpected node kind: %v", node.Kind)
+	}
+	return nil
+}
+
+// InlineKeyboardForeach is an inline keyboard with a button for each item
+// of loaded data array or state list.
+type InlineKeyboardForeach struct {
+	// Foreach is a path to array, e.g. `data`, `data.items` or `state.<key>`.
+	Foreach string `yaml:"foreach"`
+	// Button is a template of button for each item.
+	Button InlineButton `yaml:"button"`
+	// Columns is a number of buttons in a row, default is 1.
+	Columns int `yaml:"columns"`
+}
+
+func (k *InlineKeyboardForeach) validate() []error {
+	var errs []error
+	if k.Foreach != "data" && !strings.HasPrefix(k.Foreach, "data.") && !strings.HasPrefix(k.Foreach, "state.") {
+		errs = append(errs, fmt.Errorf("invalid inline keyboard foreach path %q", k.Foreach))
+	}
+	if k.Button.Text == "" {
+		errs = append(errs, errors.New("empty inline keyboard foreach button text"))
+	}
+	if k.Button.URL == "" && k.Button.Callback == "" && k.Button.WebApp == "" {
+		errs = append(errs, errors.New("empty inline keyboard foreach button action"))
+	}
+	if k.Columns < 0 {
+		errs = append(errs, fmt.Errorf("invalid inline keyboard columns %d", k.Columns))
+	}
+	return errs
 }
 
 func (r *ReplyMarkup) validate() []error {
-	if len(r.Keyboard) == 0 && len(r.InlineKeyboard) == 0 {
+	hasKeyboard := len(r.Keyboard) > 0
+	hasInline := len(r.InlineKeyboard) > 0 || r.InlineForeach != nil
+	if !hasKeyboard && !hasInline && !r.RemoveKeyboard && !r.ForceReply {
 		return []error{errors.New("empty reply markup")}
 	}
 	errs := make([]error, 0)
+	if r.RemoveKeyboard && (hasKeyboard || hasInline || r.ForceReply) {
+		errs = append(errs, errors.New("remove keyboard with other reply markup"))
+	}
+	if r.ForceReply && (hasKeyboard || hasInline) {
+		errs = append(errs, errors.New("force reply with other reply markup"))
+	}
+	if (r.OneTime || r.Resize.Valid) && !hasKeyboard {
+		errs = append(errs, errors.New("one time or resize option without keyboard"))
+	}
+	if r.Placeholder != "" && !hasKeyboard && !r.ForceReply {
+		errs = append(errs, errors.New("placeholder without keyboard or force reply"))
+	}
+	if r.Selective && !hasKeyboard && !r.RemoveKeyboard && !r.ForceReply {
+		errs = append(errs, errors.New("selective option without keyboard, remove keyboard or force reply"))
+	}
+	if r.InlineForeach != nil {
+		errs = append(errs, r.InlineForeach.validate()...)
+	}
+	requests := make(map[int]struct{})
 	for i, row := range r.Keyboard {
 		if len(row) == 0 {
 			errs = append(errs, fmt.Errorf("empty keyboard row %d", i))
 		}
 		for j, button := range row {
-			if button == "" {
+			if button.Text == "" {
 				errs = append(errs, fmt.Errorf("empty keyboard button %d:%d", i, j))
 			}
+			for _, err := range button.validate() {
+				errs = append(errs, fmt.Errorf("keyboard button %d:%d: %w", i, j, err))
+			}
+			if id, ok := button.requestID(); ok {
+				if _, dup := requests[id]; dup {
+					errs = append(errs, fmt.Errorf("duplicate keyboard button request ID %d", id))
+				}
+				requests[id] = struct{}{}
+			}
 		}
 	}
 
@@ -169,7 +485,7 @@ func (r *ReplyMarkup) validate() []error {
 			if button.Text == "" {
 				errs = append(errs, fmt.Errorf("empty inline keyboard button %d:%d", i, j))
 			}
-			if button.URL == "" && button.Callback == "" {
+			if button.URL == "" && button.Callback == "" && button.WebApp == "" {
 				errs = append(errs, fmt.Errorf("empty inline keyboard button action %d:%d", i, j))
 			}
 		}
@@ -189,18 +505,32 @@ func (r *CallbackReply) validate() []error {
 	return []error{}
 }
 
+// FileReply sends a file from assets or by remote file ID.
 type FileReply struct {
+	// Name of the file displayed in the chat, it's required for asset file.
 	Name string `yaml:"name"`
-	Key  string `yaml:"key"`
+	// Key of the file in assets provider.
+	Key string `yaml:"key"`
+	// FileID of the file uploaded to Telegram, or URL of the file, it's interpolated.
+	FileID string `yaml:"fileId"`
+	// Caption of the file, it's interpolated.
+	Caption   string    `yaml:"caption"`
+	ParseMode ParseMode `yaml:"parseMode"`
 }
 
 func (r *FileReply) validate() []error {
 	var errs []error
-	if r.Name == "" {
-		errs = append(errs, errors.New("empty image name"))
+	if r.Key == "" && r.FileID == "" {
+		errs = append(errs, errors.New("empty file key or file id"))
+	}
+	if r.Key != "" && r.FileID != "" {
+		errs = append(errs, errors.New("both file key and file id are set"))
 	}
-	if r.Key == "" {
-		errs = append(errs, errors.New("empty image file"))
+	if r.Key != "" && r.Name == "" {
+		errs = append(errs, errors.New("empty file name"))
+	}
+	if r.ParseMode != "" {
+		errs = append(errs, r.ParseMode.validate()...)
 	}
 	return errs
 }
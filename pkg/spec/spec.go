@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"time"
 
 	"github.com/caarlos0/env/v6"
 	"gopkg.in/yaml.v3"
@@ -25,6 +26,12 @@ type Bot struct {
 	Debug    bool              `yaml:"debug"`
 	Handlers []*Handler        `yaml:"handlers"`
 	Api      *API              `yaml:"api"`
+	// Support enables live support relay.
+	Support *Support `yaml:"support"`
+	// Commands menu of the bot, it's updated on start.
+	Commands []*Command `yaml:"commands"`
+	// Profile of the bot, it's updated on start.
+	Profile *Profile `yaml:"profile"`
 }
 
 // Handler specification declares bot handlers.
@@ -38,6 +45,11 @@ type Handler struct {
 	Context  *Context    `yaml:"context"`
 	Data     *Data       `yaml:"data"`
 	Validate *Validators `yaml:"validate"`
+	// Priority of the handler, handlers with higher priority are
+	// executed first, default priority is 0.
+	Priority int `yaml:"priority"`
+	// Final stops update handling after this handler.
+	Final bool `yaml:"final"`
 }
 
 // ErrNoTriggerConfig is an error for missing trigger configuration.
@@ -58,6 +70,9 @@ func (h *Handler) validate() error {
 	}
 	if h.Data != nil {
 		errs = append(errs, h.Data.validate()...)
+		if h.Trigger != nil && h.Trigger.WebAppData != nil {
+			errs = append(errs, errors.New("data loader with webAppData trigger"))
+		}
 	}
 	if h.Validate != nil {
 		errs = append(errs, h.Validate.validate()...)
@@ -97,6 +112,45 @@ func (s *Spec) validate() error {
 	for _, handler := range s.Bot.Handlers {
 		errs = append(errs, handler.validate())
 	}
+	if s.Bot.Config == nil || s.Bot.Config.AdminChat == "" {
+		for _, h := range s.Bot.Handlers {
+			for _, r := range h.Replies {
+				if r.Forward != nil && r.Forward.To == "" || r.Copy != nil && r.Copy.To == "" {
+					errs = append(errs, errors.New("forward or copy reply without target chat and admin chat config"))
+				}
+			}
+		}
+	}
+	if s.Bot.Support != nil {
+		var adminChat string
+		if s.Bot.Config != nil {
+			adminChat = s.Bot.Config.AdminChat
+		}
+		errs = append(errs, s.Bot.Support.validate(adminChat)...)
+	}
+	errs = append(errs, validateCommands(s.Bot.Commands)...)
+	if s.Bot.Profile != nil {
+		errs = append(errs, s.Bot.Profile.validate()...)
+	}
+	paginates := make(map[string]struct{})
+	for _, h := range s.Bot.Handlers {
+		for _, r := range h.Replies {
+			if r.Paginate == nil {
+				continue
+			}
+			if _, ok := paginates[r.Paginate.Name]; ok {
+				errs = append(errs, fmt.Errorf("duplicate paginate name %q", r.Paginate.Name))
+			}
+			paginates[r.Paginate.Name] = struct{}{}
+		}
+	}
+	if s.Bot.Config != nil && s.Bot.Config.UpdateTimeout != "" {
+		if d, err := time.ParseDuration(s.Bot.Config.UpdateTimeout); err != nil {
+			errs = append(errs, fmt.Errorf("invalid update timeout %q: %w", s.Bot.Config.UpdateTimeout, err))
+		} else if d <= 0 {
+			errs = append(errs, fmt.Errorf("update timeout %q should be positive", s.Bot.Config.UpdateTimeout))
+		}
+	}
 	// TODO: move from here or rename method
 	if s.Bot.Config == nil {
 		s.Bot.Config = &Config{
@@ -25,3 +25,832 @@ func TestTriggerDecode(t *testing.T) {
 		})
 	}
 }
+
+func TestMessageTriggerPattern(t *testing.T) {
+	var tr Trigger
+	err := yaml.Unmarshal([]byte(`message: {pattern: '^order (?P<id>\d+)$'}`), &tr)
+	require.NoError(t, err)
+	require.NotNil(t, tr.Message.Pattern)
+	require.Equal(t, []string{"order 1234", "1234"}, tr.Message.Pattern.FindStringSubmatch("order 1234"))
+	require.NoError(t, tr.validate())
+
+	err = yaml.Unmarshal([]byte(`message: {pattern: '(unclosed'}`), &tr)
+	require.Error(t, err)
+}
+
+func TestMessageTriggerCommandArgs(t *testing.T) {
+	var tr Trigger
+	err := yaml.Unmarshal([]byte(`message: {command: start, args: [ref], payload: '^ref_\w+$'}`), &tr)
+	require.NoError(t, err)
+	require.Equal(t, []string{"ref"}, tr.Message.Args)
+	require.True(t, tr.Message.Payload.MatchString("ref_abc"))
+	require.NoError(t, tr.validate())
+
+	err = yaml.Unmarshal([]byte(`message: {text: hello, args: [ref]}`), &tr)
+	require.NoError(t, err)
+	require.Error(t, tr.validate())
+}
+
+func TestMessageTriggerMedia(t *testing.T) {
+	var tr Trigger
+	err := yaml.Unmarshal([]byte(`message: {photo: true, document: {mime: application/pdf}}`), &tr)
+	require.NoError(t, err)
+	require.True(t, tr.Message.Photo)
+	require.Equal(t, []string{"application/pdf"}, []string(tr.Message.Document.Mime))
+	require.True(t, tr.Message.HasMedia())
+	require.NoError(t, tr.validate())
+
+	tr = Trigger{}
+	err = yaml.Unmarshal([]byte(`message: {document: true}`), &tr)
+	require.NoError(t, err)
+	require.NotNil(t, tr.Message.Document)
+	require.Empty(t, tr.Message.Document.Mime)
+
+	err = yaml.Unmarshal([]byte(`message: {document: pdf}`), &tr)
+	require.Error(t, err)
+}
+
+func TestInlineTrigger(t *testing.T) {
+	var tr Trigger
+	err := yaml.Unmarshal([]byte(`inlineQuery: '^find (?P<term>\w+)$'`), &tr)
+	require.NoError(t, err)
+	require.True(t, tr.InlineQuery.Pattern.MatchString("find cats"))
+	require.NoError(t, tr.validate())
+
+	tr = Trigger{}
+	err = yaml.Unmarshal([]byte(`chosenInlineResult: {}`), &tr)
+	require.NoError(t, err)
+	require.NotNil(t, tr.ChosenInlineResult)
+	require.Nil(t, tr.ChosenInlineResult.Pattern)
+
+	tr = Trigger{}
+	err = yaml.Unmarshal([]byte(`{inlineQuery: {}, message: hello}`), &tr)
+	require.NoError(t, err)
+	require.ErrorIs(t, tr.validate(), ErrInvalidTriggerCombination)
+}
+
+func TestInlineResultsReply(t *testing.T) {
+	var r Reply
+	err := yaml.Unmarshal([]byte(`inlineResults: {foreach: data.items, article: {title: "${item.name}", text: "${item.name}"}}`), &r)
+	require.NoError(t, err)
+	require.Empty(t, r.validate())
+
+	r = Reply{}
+	err = yaml.Unmarshal([]byte(`inlineResults: {foreach: data.items}`), &r)
+	require.NoError(t, err)
+	require.NotEmpty(t, r.validate())
+}
+
+func TestCallbackTrigger(t *testing.T) {
+	var tr Trigger
+	err := yaml.Unmarshal([]byte(`callback: "item:{id}"`), &tr)
+	require.NoError(t, err)
+	require.Equal(t, "item:{id}", tr.Callback.Data)
+	require.Empty(t, tr.Callback.Template, "data placeholders require template option")
+	require.NoError(t, tr.validate())
+
+	tr = Trigger{}
+	err = yaml.Unmarshal([]byte(`callback: {template: "item:{id}"}`), &tr)
+	require.NoError(t, err)
+	require.Equal(t, "item:{id}", tr.Callback.Template)
+	require.NoError(t, tr.validate())
+
+	tr = Trigger{}
+	err = yaml.Unmarshal([]byte(`callback: {pattern: '^page:(?P<num>\d+)$'}`), &tr)
+	require.NoError(t, err)
+	require.True(t, tr.Callback.Pattern.MatchString("page:2"))
+	require.NoError(t, tr.validate())
+
+	tr = Trigger{}
+	err = yaml.Unmarshal([]byte(`callback: {prefix: "item:", data: "item:1"}`), &tr)
+	require.NoError(t, err)
+	require.Error(t, tr.validate())
+
+	tr = Trigger{}
+	err = yaml.Unmarshal([]byte(`callback: {template: "item:{id}", data: "item:1"}`), &tr)
+	require.NoError(t, err)
+	require.Error(t, tr.validate())
+}
+
+func TestChatMemberTriggers(t *testing.T) {
+	var tr Trigger
+	err := yaml.Unmarshal([]byte(`myChatMember: kicked`), &tr)
+	require.NoError(t, err)
+	require.Equal(t, []string{"kicked"}, []string(tr.MyChatMember.Status))
+	require.NoError(t, tr.validate())
+
+	tr = Trigger{}
+	err = yaml.Unmarshal([]byte(`chatMember: {status: member, oldStatus: [left, kicked]}`), &tr)
+	require.NoError(t, err)
+	require.Equal(t, []string{"left", "kicked"}, []string(tr.ChatMember.OldStatus))
+	require.NoError(t, tr.validate())
+
+	tr = Trigger{}
+	err = yaml.Unmarshal([]byte(`chatMember: {status: joined}`), &tr)
+	require.NoError(t, err)
+	require.Error(t, tr.validate())
+
+	tr = Trigger{}
+	err = yaml.Unmarshal([]byte(`chatJoinRequest: true`), &tr)
+	require.NoError(t, err)
+	require.NotNil(t, tr.ChatJoinRequest)
+	require.NoError(t, tr.validate())
+
+	err = yaml.Unmarshal([]byte(`chatJoinRequest: false`), &tr)
+	require.Error(t, err)
+}
+
+func TestScheduleTrigger(t *testing.T) {
+	var tr Trigger
+	err := yaml.Unmarshal([]byte(`schedule: "0 9 * * MON"`), &tr)
+	require.NoError(t, err)
+	require.Equal(t, "0 9 * * MON", tr.Schedule.Cron)
+	require.NoError(t, tr.validate())
+
+	tr = Trigger{}
+	err = yaml.Unmarshal([]byte(`{schedule: {cron: "@daily", chats: [1, 2]}, state: [{key: subscribed, eq: "true"}]}`), &tr)
+	require.NoError(t, err)
+	require.Equal(t, []int64{1, 2}, tr.Schedule.Chats)
+	require.NoError(t, tr.validate())
+
+	tr = Trigger{}
+	err = yaml.Unmarshal([]byte(`schedule: "every monday"`), &tr)
+	require.NoError(t, err)
+	require.Error(t, tr.validate())
+}
+
+func TestDelayReply(t *testing.T) {
+	var h Handler
+	err := yaml.Unmarshal([]byte(`{on: {delayed: reminder}, reply: [{message: hi}, {delay: {cancel: nudge}}]}`), &h)
+	require.NoError(t, err)
+	require.Equal(t, "reminder", h.Trigger.Delayed)
+	require.NoError(t, h.Trigger.validate())
+	require.Empty(t, h.Replies[1].validate())
+
+	var r Reply
+	err = yaml.Unmarshal([]byte(`delay: {event: reminder, after: 2h}`), &r)
+	require.NoError(t, err)
+	require.Empty(t, r.validate())
+
+	err = yaml.Unmarshal([]byte(`delay: {event: reminder, after: two hours}`), &r)
+	require.NoError(t, err)
+	require.NotEmpty(t, r.validate())
+
+	r = Reply{}
+	err = yaml.Unmarshal([]byte(`delay: {event: reminder}`), &r)
+	require.NoError(t, err)
+	require.NotEmpty(t, r.validate())
+}
+
+func TestContextTTL(t *testing.T) {
+	var h Handler
+	err := yaml.Unmarshal([]byte(`{on: /checkout, context: {set: checkout, ttl: 15m}, reply: [{message: hi}]}`), &h)
+	require.NoError(t, err)
+	require.Equal(t, "15m", h.Context.TTL)
+	require.Empty(t, h.Context.validate())
+
+	h.Context.TTL = "soon"
+	require.NotEmpty(t, h.Context.validate())
+
+	var tr Trigger
+	err = yaml.Unmarshal([]byte(`contextExpired: checkout`), &tr)
+	require.NoError(t, err)
+	require.Equal(t, "checkout", tr.ContextExpired)
+	require.NoError(t, tr.validate())
+}
+
+func TestTriggerChatTypeAndSender(t *testing.T) {
+	var tr Trigger
+	err := yaml.Unmarshal([]byte(`{message: /ban, chatType: [group, supergroup], sender: {admin: true, ids: [1]}}`), &tr)
+	require.NoError(t, err)
+	require.Equal(t, []string{"group", "supergroup"}, []string(tr.ChatType))
+	require.True(t, tr.Sender.Admin)
+	require.Equal(t, []int64{1}, tr.Sender.IDs)
+	require.NoError(t, tr.validate())
+
+	tr = Trigger{}
+	err = yaml.Unmarshal([]byte(`{message: /start, chatType: chat}`), &tr)
+	require.NoError(t, err)
+	require.Error(t, tr.validate())
+
+	tr = Trigger{}
+	err = yaml.Unmarshal([]byte(`{fallback: true, chatType: private}`), &tr)
+	require.NoError(t, err)
+	require.ErrorIs(t, tr.validate(), ErrInvalidTriggerCombination)
+
+	tr = Trigger{}
+	err = yaml.Unmarshal([]byte(`{message: /start, sender: {}}`), &tr)
+	require.NoError(t, err)
+	require.Error(t, tr.validate())
+}
+
+func TestTriggerHasUpdateType(t *testing.T) {
+	for src, expect := range map[string]bool{
+		`{context: waiting_name}`:                          false,
+		`{state: [{key: role, eq: admin}]}`:                false,
+		`{chatType: private}`:                              false,
+		`{message: /start, context: waiting_name}`:         true,
+		`{callback: {data: ok}, state: [{key: a, eq: b}]}`: true,
+		`{pollAnswer: true}`:                               true,
+	} {
+		var tr Trigger
+		require.NoError(t, yaml.Unmarshal([]byte(src), &tr))
+		require.Equal(t, expect, tr.HasUpdateType(), src)
+	}
+}
+
+func TestTriggerCombinators(t *testing.T) {
+	var tr Trigger
+	err := yaml.Unmarshal([]byte(`
+message: /bonus
+state:
+- key: balance
+  gte: 100
+any:
+- state: [{key: role, eq: admin}]
+- state: [{key: role, in: [vip, premium]}]
+not:
+  if: [{value: "${user.username}", matches: "^test"}]
+`), &tr)
+	require.NoError(t, err)
+	require.Equal(t, "100", tr.State[0].Gte)
+	require.Len(t, tr.Any, 2)
+	require.Equal(t, "admin", tr.Any[0].State[0].Eq)
+	require.Equal(t, []string{"vip", "premium"}, []string(tr.Any[1].State[0].In))
+	require.Equal(t, "${user.username}", tr.Not.If[0].Value)
+	require.True(t, tr.Not.If[0].Matches.MatchString("tester"))
+	require.NoError(t, tr.validate())
+
+	tr = Trigger{}
+	err = yaml.Unmarshal([]byte(`{if: [{value: "${data.balance}", gt: 0}]}`), &tr)
+	require.NoError(t, err)
+	require.NoError(t, tr.validate())
+
+	tr = Trigger{}
+	err = yaml.Unmarshal([]byte(`{if: [{value: "${data.balance}"}]}`), &tr)
+	require.NoError(t, err)
+	require.Error(t, tr.validate())
+
+	tr = Trigger{}
+	err = yaml.Unmarshal([]byte(`{message: /start, state: [{key: role, present: true, eq: admin}]}`), &tr)
+	require.NoError(t, err)
+	require.NoError(t, tr.validate(), "present and eq are exclusive")
+
+	tr = Trigger{}
+	err = yaml.Unmarshal([]byte(`{message: /start, state: [{key: balance, eq: "0", gt: 10}]}`), &tr)
+	require.NoError(t, err)
+	require.Error(t, tr.validate())
+
+	tr = Trigger{}
+	err = yaml.Unmarshal([]byte(`{message: /start, any: [{fallback: true}]}`), &tr)
+	require.NoError(t, err)
+	require.ErrorIs(t, tr.validate(), ErrInvalidTriggerCombination)
+
+	tr = Trigger{}
+	err = yaml.Unmarshal([]byte(`{message: /start, not: {}}`), &tr)
+	require.NoError(t, err)
+	require.ErrorIs(t, tr.validate(), ErrEmptyTrigger)
+}
+
+func TestSpecWarnings(t *testing.T) {
+	var s Spec
+	err := yaml.Unmarshal([]byte(`
+bot:
+  handlers:
+  - id: hello
+    on: hello
+    reply: [{message: Hello}]
+  - id: greeting
+    on:
+      message:
+        pattern: "^(hello|hi)$"
+    reply: [{message: Hi}]
+  - id: admin-hello
+    on:
+      message: hello
+      state: [{key: role, eq: admin}]
+    priority: 1
+    final: true
+    reply: [{message: Hello admin}]
+  - on:
+      message: /start
+      state: [{key: started, eq: "true"}]
+    reply: [{message: Welcome back}]
+  - on:
+      message: /start
+      state: [{key: started, present: false}]
+    reply: [{message: Welcome}]
+  - id: help
+    on: /help
+    priority: 1
+    reply: [{message: Help}]
+  - id: help-more
+    on: /help
+    reply: [{message: More help}]
+`), &s)
+	require.NoError(t, err)
+	require.Equal(t, 1, s.Bot.Handlers[2].Priority)
+	require.True(t, s.Bot.Handlers[2].Final)
+	warns := s.Warnings()
+	require.Len(t, warns, 2)
+	require.Contains(t, warns[0], `"hello" and "greeting"`)
+	require.Contains(t, warns[1], `"help" and "help-more"`, "priority without final")
+}
+
+func TestMessageUpdateTriggers(t *testing.T) {
+	var tr Trigger
+	err := yaml.Unmarshal([]byte(`{channelPost: {pattern: "^#news"}}`), &tr)
+	require.NoError(t, err)
+	require.Equal(t, "^#news", tr.ChannelPost.Pattern.String())
+	require.Equal(t, []TriggerType{TriggerTypeChannelPost}, tr.Types())
+	require.NoError(t, tr.validate())
+
+	tr = Trigger{}
+	err = yaml.Unmarshal([]byte(`{editedMessage: {command: start}}`), &tr)
+	require.NoError(t, err)
+	require.Equal(t, "start", tr.EditedMessage.Command)
+	require.NoError(t, tr.validate())
+
+	tr = Trigger{}
+	err = yaml.Unmarshal([]byte(`{editedChannelPost: {}}`), &tr)
+	require.NoError(t, err)
+	require.Error(t, tr.validate())
+
+	tr = Trigger{}
+	err = yaml.Unmarshal([]byte(`{messageReaction: true}`), &tr)
+	require.NoError(t, err)
+	require.Empty(t, tr.MessageReaction.Emoji)
+	require.NoError(t, tr.validate())
+
+	tr = Trigger{}
+	err = yaml.Unmarshal([]byte(`{messageReaction: ["👍", "❤"]}`), &tr)
+	require.NoError(t, err)
+	require.Equal(t, []string{"👍", "❤"}, []string(tr.MessageReaction.Emoji))
+
+	tr = Trigger{}
+	err = yaml.Unmarshal([]byte(`{message: hello, channelPost: hello}`), &tr)
+	require.NoError(t, err)
+	require.ErrorIs(t, tr.validate(), ErrInvalidTriggerCombination)
+}
+
+func TestPoll(t *testing.T) {
+	var r Reply
+	err := yaml.Unmarshal([]byte(`
+poll:
+  name: capitals
+  question: "Capital of France?"
+  options: [Rome, Paris, Berlin]
+  quiz: true
+  correctOption: 1
+`), &r)
+	require.NoError(t, err)
+	require.Equal(t, "capitals", r.Poll.Name)
+	require.Equal(t, []string{"Rome", "Paris", "Berlin"}, []string(r.Poll.Options))
+	require.Empty(t, r.validate())
+
+	r.Poll.CorrectOption = "3"
+	require.NotEmpty(t, r.validate())
+
+	r = Reply{}
+	err = yaml.Unmarshal([]byte(`{poll: {question: "Lunch?", foreach: data.items, option: "${item.name}", quiz: true}}`), &r)
+	require.NoError(t, err)
+	require.NotEmpty(t, r.validate())
+
+	var tr Trigger
+	err = yaml.Unmarshal([]byte(`{pollAnswer: {poll: capitals, correct: true}}`), &tr)
+	require.NoError(t, err)
+	require.Equal(t, "capitals", tr.PollAnswer.Poll)
+	require.True(t, tr.PollAnswer.Correct.Valid)
+	require.NoError(t, tr.validate())
+
+	tr = Trigger{}
+	err = yaml.Unmarshal([]byte(`{pollAnswer: true}`), &tr)
+	require.NoError(t, err)
+	require.Empty(t, tr.PollAnswer.Poll)
+	require.NoError(t, tr.validate())
+}
+
+func TestWebApp(t *testing.T) {
+	var r Reply
+	err := yaml.Unmarshal([]byte(`
+message:
+  text: Shop
+  markup:
+    keyboard:
+      - - text: Order
+          webApp: https://example.com/webapp/
+        - Help
+    inlineKeyboard:
+      - - text: Open
+          webApp: https://example.com/webapp/
+`), &r)
+	require.NoError(t, err)
+	require.Equal(t, "Order", r.Message.Markup.Keyboard[0][0].Text)
+	require.Equal(t, "https://example.com/webapp/", r.Message.Markup.Keyboard[0][0].WebApp)
+	require.Equal(t, "Help", r.Message.Markup.Keyboard[0][1].Text)
+	require.Equal(t, "https://example.com/webapp/", r.Message.Markup.InlineKeyboard[0][0].WebApp)
+	require.Empty(t, r.validate())
+
+	var tr Trigger
+	err = yaml.Unmarshal([]byte(`{webAppData: Order}`), &tr)
+	require.NoError(t, err)
+	require.Equal(t, "Order", tr.WebAppData.Button)
+	require.NoError(t, tr.validate())
+
+	tr = Trigger{}
+	err = yaml.Unmarshal([]byte(`{webAppData: true}`), &tr)
+	require.NoError(t, err)
+	require.Empty(t, tr.WebAppData.Button)
+
+	var h ApiHandler
+	err = yaml.Unmarshal([]byte(`{id: order, webApp: true, actions: [{send-message: Done}]}`), &h)
+	require.NoError(t, err)
+	require.True(t, h.WebApp)
+}
+
+func TestInlineKeyboardForeach(t *testing.T) {
+	var r Reply
+	err := yaml.Unmarshal([]byte(`
+message:
+  text: Products
+  markup:
+    inlineKeyboard:
+      foreach: data.products
+      columns: 2
+      button:
+        text: ${item.name}
+        callback: "product:${item.id}"
+`), &r)
+	require.NoError(t, err)
+	require.Empty(t, r.Message.Markup.InlineKeyboard)
+	require.NotNil(t, r.Message.Markup.InlineForeach)
+	require.Equal(t, "data.products", r.Message.Markup.InlineForeach.Foreach)
+	require.Equal(t, 2, r.Message.Markup.InlineForeach.Columns)
+	require.Equal(t, "product:${item.id}", r.Message.Markup.InlineForeach.Button.Callback)
+	require.Empty(t, r.validate())
+
+	r = Reply{}
+	err = yaml.Unmarshal([]byte(`{message: {text: Tags, markup: {inlineKeyboard: {foreach: tags, button: {text: "${item}"}}}}}`), &r)
+	require.NoError(t, err)
+	require.Len(t, r.validate(), 2)
+}
+
+func TestPaginate(t *testing.T) {
+	var r Reply
+	err := yaml.Unmarshal([]byte(`
+paginate:
+  name: products
+  foreach: data.products
+  pageSize: 3
+  header: "Products, page ${page} of ${pages}:"
+  item: "${item.name} - ${item.price}"
+`), &r)
+	require.NoError(t, err)
+	require.Equal(t, "products", r.Paginate.Name)
+	require.Equal(t, 3, r.Paginate.PageSize)
+	require.Empty(t, r.validate())
+
+	r.Paginate.Name = "products:all"
+	require.Len(t, r.validate(), 1)
+
+	var s Spec
+	err = yaml.Unmarshal([]byte(`
+bot:
+  handlers:
+    - on: /a
+      reply:
+        - paginate: {name: list, foreach: state.items, item: "${item}"}
+    - on: /b
+      reply:
+        - paginate: {name: list, foreach: data, item: "${item}"}
+`), &s)
+	require.NoError(t, err)
+	require.ErrorContains(t, s.Validate(), `duplicate paginate name "list"`)
+}
+
+func TestMediaReplies(t *testing.T) {
+	var rs []*Reply
+	err := yaml.Unmarshal([]byte(`
+- video:
+    key: intro.mp4
+    name: intro.mp4
+    caption: "Welcome, ${user.first_name}"
+- sticker:
+    fileId: CAACAgIAAxkBAAEB
+- mediaGroup:
+    - type: photo
+      key: step1.png
+      name: step1.png
+      caption: Step 1
+    - type: video
+      fileId: https://example.com/step2.mp4
+- location: {latitude: "52.52", longitude: "13.405"}
+- venue: {latitude: "${data.lat}", longitude: "${data.lon}", title: Office, address: Main st. 1}
+- contact: {phoneNumber: "+100000000", firstName: Support}
+- dice: true
+- dice: "🎯"
+`), &rs)
+	require.NoError(t, err)
+	for _, r := range rs {
+		require.Empty(t, r.validate())
+	}
+	require.Equal(t, "Welcome, ${user.first_name}", rs[0].Video.Caption)
+	require.Len(t, rs[2].MediaGroup, 2)
+	require.Equal(t, MediaVideo, rs[2].MediaGroup[1].Type)
+	require.Equal(t, "https://example.com/step2.mp4", rs[2].MediaGroup[1].FileID)
+	require.Empty(t, rs[6].Dice.Emoji)
+	require.Equal(t, "🎯", rs[7].Dice.Emoji)
+
+	err = yaml.Unmarshal([]byte(`
+- mediaGroup: [{type: photo, fileId: abc}]
+- location: {latitude: "91", longitude: "0"}
+- audio: {key: song.mp3}
+- dice: "🎮"
+`), &rs)
+	require.NoError(t, err)
+	for _, r := range rs {
+		require.NotEmpty(t, r.validate())
+	}
+}
+
+func TestForward(t *testing.T) {
+	var rs []*Reply
+	err := yaml.Unmarshal([]byte(`
+- forward: true
+- forward: "${state.operator_chat}"
+- copy:
+    to: "@moderation"
+    caption: "From ${user.username}"
+`), &rs)
+	require.NoError(t, err)
+	require.Empty(t, rs[0].Forward.To)
+	require.Equal(t, "${state.operator_chat}", rs[1].Forward.To)
+	require.Equal(t, "@moderation", rs[2].Copy.To)
+	for _, r := range rs {
+		require.Empty(t, r.validate())
+	}
+
+	var s Spec
+	err = yaml.Unmarshal([]byte(`
+bot:
+  handlers:
+    - on: /report
+      reply: [{forward: true}]
+`), &s)
+	require.NoError(t, err)
+	require.ErrorContains(t, s.Validate(), "without target chat")
+	s.Bot.Config.AdminChat = "-100123"
+	require.NoError(t, s.Validate())
+}
+
+func TestSupport(t *testing.T) {
+	var s Spec
+	err := yaml.Unmarshal([]byte(`
+bot:
+  config:
+    adminChat: "-1001234567890"
+  support:
+    context: support
+    topic: 12
+    closed: Thank you, the session is closed.
+  handlers:
+    - on: /support
+      context:
+        set: support
+      reply: [{message: Write your question}]
+`), &s)
+	require.NoError(t, err)
+	require.Equal(t, "support", s.Bot.Support.Context)
+	require.Equal(t, 12, s.Bot.Support.Topic)
+	require.NoError(t, s.Validate())
+
+	s.Bot.Support.Chat = "@operators"
+	require.ErrorContains(t, s.Validate(), "invalid support chat ID")
+	s.Bot.Support.Chat = ""
+	s.Bot.Config.AdminChat = ""
+	require.ErrorContains(t, s.Validate(), "support chat without admin chat config")
+}
+
+func TestSendOptions(t *testing.T) {
+	var r Reply
+	err := yaml.Unmarshal([]byte(`
+message: Hello
+replyTo: true
+disableNotification: true
+protectContent: true
+disableLinkPreview: true
+threadId: "${state.topic}"
+`), &r)
+	require.NoError(t, err)
+	require.Equal(t, "Hello", r.Message.Text)
+	require.Equal(t, SendOptions{
+		ReplyTo: true, DisableNotification: true, ProtectContent: true,
+		DisableLinkPreview: true, ThreadID: "${state.topic}",
+	}, r.SendOptions)
+	require.Empty(t, r.validate())
+
+	r.ThreadID = "general"
+	require.Len(t, r.validate(), 1)
+}
+
+func TestChatActionAndPause(t *testing.T) {
+	var s Spec
+	err := yaml.Unmarshal([]byte(`
+bot:
+  config:
+    updateTimeout: 5s
+  handlers:
+  - id: order
+    on:
+      message: /order
+    reply:
+    - message: Checking...
+    - typing: true
+      pause: 1500ms
+    - chatAction: upload_photo
+      pause: 4s
+      message: Done
+`), &s)
+	require.NoError(t, err)
+	require.NoError(t, s.validate())
+	replies := s.Bot.Handlers[0].Replies
+	require.Equal(t, "typing", replies[1].Action())
+	require.Equal(t, "1500ms", replies[1].Pause)
+	require.Equal(t, "upload_photo", replies[2].Action())
+	warns := s.Warnings()
+	require.Len(t, warns, 1)
+	require.Contains(t, warns[0], `"order" pauses for 5.5s`)
+
+	for _, src := range []string{
+		`{chatAction: dancing}`,
+		`{pause: soon}`,
+		`{pause: -1s}`,
+		`{typing: true, chatAction: upload_photo}`,
+	} {
+		var r Reply
+		require.NoError(t, yaml.Unmarshal([]byte(src), &r))
+		require.NotEmpty(t, r.validate(), src)
+	}
+}
+
+func TestKeyboardOptions(t *testing.T) {
+	var m MessageReply
+	err := yaml.Unmarshal([]byte(`
+text: Share your phone number
+markup:
+  keyboard:
+  - [{text: Share contact, requestContact: true}]
+  - [{text: Pick friends, requestUsers: {id: 1, bot: false, max: 3}}, {text: Pick group, requestChat: {id: 2}}]
+  - [Cancel]
+  oneTime: true
+  resize: true
+  placeholder: Phone number
+`), &m)
+	require.NoError(t, err)
+	require.Empty(t, m.validate())
+	kb := m.Markup.Keyboard
+	require.True(t, kb[0][0].RequestContact)
+	require.Equal(t, 3, kb[1][0].RequestUsers.Max)
+	require.False(t, *kb[1][0].RequestUsers.Bot)
+	require.Equal(t, 2, kb[1][1].RequestChat.ID)
+	require.Equal(t, "Cancel", kb[2][0].Text)
+	require.True(t, m.Markup.OneTime)
+	require.True(t, m.Markup.ResizeKeyboard())
+	require.Equal(t, "Phone number", m.Markup.Placeholder)
+
+	m = MessageReply{}
+	require.NoError(t, yaml.Unmarshal([]byte(`{text: Menu, markup: {keyboard: [[Help]]}}`), &m))
+	require.True(t, m.Markup.ResizeKeyboard(), "resize by default")
+	m = MessageReply{}
+	require.NoError(t, yaml.Unmarshal([]byte(`{text: Menu, markup: {keyboard: [[Help]], resize: false}}`), &m))
+	require.False(t, m.Markup.ResizeKeyboard())
+	require.Empty(t, m.validate())
+
+	m = MessageReply{}
+	require.NoError(t, yaml.Unmarshal([]byte(`{text: Bye, markup: {removeKeyboard: true}}`), &m))
+	require.Empty(t, m.validate())
+
+	for _, src := range []string{
+		`{text: Hi, markup: {removeKeyboard: true, keyboard: [[A]]}}`,
+		`{text: Hi, markup: {forceReply: true, inlineKeyboard: [[{text: A, callback: a}]]}}`,
+		`{text: Hi, markup: {forceReply: true, oneTime: true}}`,
+		`{text: Hi, markup: {keyboard: [[{text: A, requestContact: true, requestLocation: true}]]}}`,
+		`{text: Hi, markup: {keyboard: [[{text: A, requestPoll: survey}]]}}`,
+		`{text: Hi, markup: {keyboard: [[{text: A, requestUsers: {id: 1}}, {text: B, requestChat: {id: 1}}]]}}`,
+	} {
+		m = MessageReply{}
+		require.NoError(t, yaml.Unmarshal([]byte(src), &m))
+		require.NotEmpty(t, m.validate(), src)
+	}
+
+	var tr Trigger
+	require.NoError(t, yaml.Unmarshal([]byte(`{message: {shared: 1}}`), &tr))
+	require.Equal(t, 1, *tr.Message.Shared)
+	require.NoError(t, tr.validate())
+}
+
+func TestModeration(t *testing.T) {
+	var replies []*Reply
+	err := yaml.Unmarshal([]byte(`
+- delete: true
+- delete: {target: reply}
+  pin: {target: reply, silent: true}
+- unpin: {all: true}
+- ban: {target: reply, until: 24h, revokeMessages: true}
+- unban: true
+- restrict:
+    target: reply
+    until: ${state.mute}
+    permissions: {sendMessages: true}
+- promote: {target: reply, pinMessages: true}
+- setChatTitle: Chat ${data.date}
+`), &replies)
+	require.NoError(t, err)
+	for _, r := range replies {
+		require.Empty(t, r.validate())
+	}
+	require.Equal(t, Target(""), replies[0].Delete.Target)
+	require.Equal(t, TargetReply, replies[1].Delete.Target)
+	require.True(t, replies[1].Pin.Silent)
+	require.True(t, replies[2].Unpin.All)
+	require.Equal(t, "24h", replies[3].Ban.Until)
+	require.NotNil(t, replies[4].Unban)
+	require.True(t, replies[5].Restrict.Permissions.SendMessages)
+	require.False(t, replies[5].Restrict.Permissions.SendMedia)
+	require.True(t, replies[6].Promote.PinMessages)
+	require.Equal(t, "Chat ${data.date}", replies[7].SetChatTitle)
+
+	for _, src := range []string{
+		`{ban: {target: admin}}`,
+		`{ban: {until: tomorrow}}`,
+		`{unpin: {all: true, target: reply}}`,
+	} {
+		var r Reply
+		require.NoError(t, yaml.Unmarshal([]byte(src), &r))
+		require.NotEmpty(t, r.validate(), src)
+	}
+	var r Reply
+	require.NoError(t, yaml.Unmarshal([]byte(`{delete: True, pin: false}`), &r))
+	require.NotNil(t, r.Delete)
+	require.Nil(t, r.Pin, "false disables the action")
+	r = Reply{}
+	require.Error(t, yaml.Unmarshal([]byte(`{pin: sometimes}`), &r))
+}
+
+func TestCommands(t *testing.T) {
+	var s Spec
+	err := yaml.Unmarshal([]byte(`
+bot:
+  commands:
+  - command: start
+    description: Start the bot
+    languages: {ru: Запустить бота}
+  - command: help
+    description: Show help
+    scope: [private, groups]
+  - command: ban
+    description: Ban the user
+    scope: admins
+  profile:
+    name: Shop bot
+    shortDescription: Online shop
+    languages:
+      ru: {name: Магазин}
+  handlers:
+  - on: {message: /start}
+    reply: [{message: Hi}]
+  - on: {message: {command: help}}
+    reply: [{message: Help}]
+  - on: {message: {command: orders}}
+    reply: [{message: Orders}]
+`), &s)
+	require.NoError(t, err)
+	require.NoError(t, s.validate())
+	require.Equal(t, "Shop bot", s.Bot.Profile.Name)
+	require.Equal(t, "Магазин", s.Bot.Profile.Languages["ru"].Name)
+
+	menus := CommandMenus(s.Bot.Commands)
+	require.Len(t, menus, 8)
+	require.Equal(t, CommandMenu{Scope: "default", Items: []MenuItem{{"start", "Start the bot"}}}, menus[0])
+	require.Equal(t, CommandMenu{Scope: "default", Language: "ru",
+		Items: []MenuItem{{"start", "Запустить бота"}}}, menus[1])
+	require.Equal(t, CommandMenu{Scope: "private", Items: []MenuItem{{"help", "Show help"}}}, menus[2])
+	require.Equal(t, CommandMenu{Scope: "private", Language: "ru"}, menus[3])
+	require.Equal(t, CommandMenu{Scope: "admins", Items: []MenuItem{{"ban", "Ban the user"}}}, menus[6])
+
+	warns := s.Warnings()
+	require.Len(t, warns, 1)
+	require.Contains(t, warns[0], "/orders is missing")
+
+	for _, src := range []string{
+		`[{command: /start, description: Start}]`,
+		`[{command: start, description: Go}]`,
+		`[{command: start, description: Start, scope: everyone}]`,
+		`[{command: start, description: Start, languages: {russian: Старт}}]`,
+		`[{command: start, description: Start}, {command: start, description: Restart}]`,
+	} {
+		var cmds []*Command
+		require.NoError(t, yaml.Unmarshal([]byte(src), &cmds))
+		require.NotEmpty(t, validateCommands(cmds), src)
+	}
+}
@@ -0,0 +1,46 @@
+package spec
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// Support configures live support relay between users and operators group.
+// User messages in the support context are copied to the operators chat,
+// and operator replies to these messages are copied back to users.
+type Support struct {
+	// Context of user chats to relay messages to operators.
+	Context string `yaml:"context"`
+	// Chat is an operators group chat ID, default is `adminChat` of config.
+	Chat string `yaml:"chat"`
+	// Topic is an optional forum topic ID of operators group.
+	Topic int `yaml:"topic"`
+	// CloseCommand closes support session, default is `close`.
+	CloseCommand string `yaml:"closeCommand"`
+	// Header is a message sent to operators before the first message of session,
+	// it's interpolated with user update.
+	Header string `yaml:"header"`
+	// Closed is a message sent to user when session is closed.
+	Closed string `yaml:"closed"`
+}
+
+func (s *Support) validate(adminChat string) []error {
+	var errs []error
+	if s.Context == "" {
+		errs = append(errs, errors.New("empty support context"))
+	}
+	chat := s.Chat
+	if chat == "" {
+		chat = adminChat
+	}
+	if chat == "" {
+		errs = append(errs, errors.New("support chat without admin chat config"))
+	} else if _, err := strconv.ParseInt(chat, 10, 64); err != nil {
+		errs = append(errs, fmt.Errorf("invalid support chat ID %q", chat))
+	}
+	if s.Topic < 0 {
+		errs = append(errs, fmt.Errorf("invalid support topic %d", s.Topic))
+	}
+	return errs
+}
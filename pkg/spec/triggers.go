@@ -5,19 +5,44 @@ import (
 	"fmt"
 	"slices"
 
+	"github.com/robfig/cron/v3"
 	"gopkg.in/yaml.v3"
 )
 
 // Trigger is a handler trigger whcich configures when the handler should be
 // executed.
 type Trigger struct {
-	Message      *MessageTrigger
-	Callback     *CallbackTrigger
-	Context      string
-	PreCheckout  *PreCheckoutTrigger
-	PostCheckout *PostCheckoutTrigger
-	State        []StateCondition
-	Fallback     bool
+	Message            *MessageTrigger
+	EditedMessage      *MessageTrigger
+	ChannelPost        *MessageTrigger
+	EditedChannelPost  *MessageTrigger
+	MessageReaction    *ReactionTrigger
+	Callback           *CallbackTrigger
+	Context            string
+	PreCheckout        *PreCheckoutTrigger
+	PostCheckout       *PostCheckoutTrigger
+	InlineQuery        *InlineTrigger
+	ChosenInlineResult *InlineTrigger
+	MyChatMember       *ChatMemberTrigger
+	ChatMember         *ChatMemberTrigger
+	ChatJoinRequest    *ChatJoinRequestTrigger
+	PollAnswer         *PollAnswerTrigger
+	WebAppData         *WebAppDataTrigger
+	Schedule           *ScheduleTrigger
+	Delayed            string
+	ContextExpired     string
+	State              []StateCondition
+	// ChatType and Sender are additional conditions for other triggers.
+	ChatType Strings
+	Sender   *SenderCondition
+	// If is a list of value conditions, top-level conditions
+	// are checked after handler data is loaded.
+	If []Condition
+	// Any, All and Not combine nested triggers.
+	Any      []*Trigger
+	All      []*Trigger
+	Not      *Trigger
+	Fallback bool
 }
 
 func (t *Trigger) UnmarshalYAML(node *yaml.Node) error {
@@ -40,23 +65,63 @@ func (t *Trigger) UnmarshalYAML(node *yaml.Node) error {
 		t.Message = &MessageTrigger{Text: s}
 	case yaml.MappingNode:
 		var schema struct {
-			Message      *MessageTrigger      `yaml:"message"`
-			Callback     *CallbackTrigger     `yaml:"callback"`
-			Context      string               `yaml:"context"`
-			PreCheckout  *PreCheckoutTrigger  `yaml:"preCheckout"`
-			PostCheckout *PostCheckoutTrigger `yaml:"postCheckout"`
-			State        []StateCondition     `yaml:"state"`
-			Fallback     bool                 `yaml:"fallback"`
+			Message            *MessageTrigger         `yaml:"message"`
+			EditedMessage      *MessageTrigger         `yaml:"editedMessage"`
+			ChannelPost        *MessageTrigger         `yaml:"channelPost"`
+			EditedChannelPost  *MessageTrigger         `yaml:"editedChannelPost"`
+			MessageReaction    *ReactionTrigger        `yaml:"messageReaction"`
+			Callback           *CallbackTrigger        `yaml:"callback"`
+			Context            string                  `yaml:"context"`
+			PreCheckout        *PreCheckoutTrigger     `yaml:"preCheckout"`
+			PostCheckout       *PostCheckoutTrigger    `yaml:"postCheckout"`
+			InlineQuery        *InlineTrigger          `yaml:"inlineQuery"`
+			ChosenInlineResult *InlineTrigger          `yaml:"chosenInlineResult"`
+			MyChatMember       *ChatMemberTrigger      `yaml:"myChatMember"`
+			ChatMember         *ChatMemberTrigger      `yaml:"chatMember"`
+			ChatJoinRequest    *ChatJoinRequestTrigger `yaml:"chatJoinRequest"`
+			PollAnswer         *PollAnswerTrigger      `yaml:"pollAnswer"`
+			WebAppData         *WebAppDataTrigger      `yaml:"webAppData"`
+			Schedule           *ScheduleTrigger        `yaml:"schedule"`
+			Delayed            string                  `yaml:"delayed"`
+			ContextExpired     string                  `yaml:"contextExpired"`
+			ChatType           Strings                 `yaml:"chatType"`
+			Sender             *SenderCondition        `yaml:"sender"`
+			State              []StateCondition        `yaml:"state"`
+			If                 []Condition             `yaml:"if"`
+			Any                []*Trigger              `yaml:"any"`
+			All                []*Trigger              `yaml:"all"`
+			Not                *Trigger                `yaml:"not"`
+			Fallback           bool                    `yaml:"fallback"`
 		}
 		if err := node.Decode(&schema); err != nil {
 			return fmt.Errorf("decode trigger: %w", err)
 		}
 		t.Message = schema.Message
+		t.EditedMessage = schema.EditedMessage
+		t.ChannelPost = schema.ChannelPost
+		t.EditedChannelPost = schema.EditedChannelPost
+		t.MessageReaction = schema.MessageReaction
 		t.Callback = schema.Callback
 		t.Context = schema.Context
 		t.PreCheckout = schema.PreCheckout
 		t.PostCheckout = schema.PostCheckout
+		t.InlineQuery = schema.InlineQuery
+		t.ChosenInlineResult = schema.ChosenInlineResult
+		t.MyChatMember = schema.MyChatMember
+		t.ChatMember = schema.ChatMember
+		t.ChatJoinRequest = schema.ChatJoinRequest
+		t.PollAnswer = schema.PollAnswer
+		t.WebAppData = schema.WebAppData
+		t.Schedule = schema.Schedule
+		t.Delayed = schema.Delayed
+		t.ContextExpired = schema.ContextExpired
+		t.ChatType = schema.ChatType
+		t.Sender = schema.Sender
 		t.State = schema.State
+		t.If = schema.If
+		t.Any = schema.Any
+		t.All = schema.All
+		t.Not = schema.Not
 		t.Fallback = schema.Fallback
 	default:
 		return fmt.Errorf("unexpected node kind: %v", node.Kind)
@@ -78,6 +143,20 @@ const (
 	TriggerTypePostCheckout
 	TriggerTypeState
 	TriggerTypeFallback
+	TriggerTypeInlineQuery
+	TriggerTypeChosenInlineResult
+	TriggerTypeMyChatMember
+	TriggerTypeChatMember
+	TriggerTypeChatJoinRequest
+	TriggerTypeSchedule
+	TriggerTypeDelayed
+	TriggerTypeContextExpired
+	TriggerTypeEditedMessage
+	TriggerTypeChannelPost
+	TriggerTypeEditedChannelPost
+	TriggerTypeMessageReaction
+	TriggerTypePollAnswer
+	TriggerTypeWebAppData
 )
 
 // Types returns a list of trigger types.
@@ -86,6 +165,18 @@ func (t *Trigger) Types() []TriggerType {
 	if t.Message != nil {
 		typ = append(typ, TriggerTypeMessage)
 	}
+	if t.EditedMessage != nil {
+		typ = append(typ, TriggerTypeEditedMessage)
+	}
+	if t.ChannelPost != nil {
+		typ = append(typ, TriggerTypeChannelPost)
+	}
+	if t.EditedChannelPost != nil {
+		typ = append(typ, TriggerTypeEditedChannelPost)
+	}
+	if t.MessageReaction != nil {
+		typ = append(typ, TriggerTypeMessageReaction)
+	}
 	if t.Callback != nil {
 		typ = append(typ, TriggerTypeCallback)
 	}
@@ -98,6 +189,36 @@ func (t *Trigger) Types() []TriggerType {
 	if t.PostCheckout != nil {
 		typ = append(typ, TriggerTypePostCheckout)
 	}
+	if t.InlineQuery != nil {
+		typ = append(typ, TriggerTypeInlineQuery)
+	}
+	if t.ChosenInlineResult != nil {
+		typ = append(typ, TriggerTypeChosenInlineResult)
+	}
+	if t.MyChatMember != nil {
+		typ = append(typ, TriggerTypeMyChatMember)
+	}
+	if t.ChatMember != nil {
+		typ = append(typ, TriggerTypeChatMember)
+	}
+	if t.ChatJoinRequest != nil {
+		typ = append(typ, TriggerTypeChatJoinRequest)
+	}
+	if t.PollAnswer != nil {
+		typ = append(typ, TriggerTypePollAnswer)
+	}
+	if t.WebAppData != nil {
+		typ = append(typ, TriggerTypeWebAppData)
+	}
+	if t.Schedule != nil {
+		typ = append(typ, TriggerTypeSchedule)
+	}
+	if t.Delayed != "" {
+		typ = append(typ, TriggerTypeDelayed)
+	}
+	if t.ContextExpired != "" {
+		typ = append(typ, TriggerTypeContextExpired)
+	}
 	if len(t.State) > 0 {
 		typ = append(typ, TriggerTypeState)
 	}
@@ -113,18 +234,48 @@ var ErrEmptyTrigger = errors.New("empty trigger")
 // ErrInvalidTriggerCombination is returned when trigger combination is invalid.
 var ErrInvalidTriggerCombination = errors.New("invalid trigger combination")
 
+// HasUpdateType checks if trigger has a type of telegram update, context and state
+// types are conditions of any update, so they are not update types.
+func (t *Trigger) HasUpdateType() bool {
+	for _, typ := range t.Types() {
+		if typ != TriggerTypeContext && typ != TriggerTypeState {
+			return true
+		}
+	}
+	return false
+}
+
+// HasConditions checks if trigger has additional conditions
+// which could be used without trigger types.
+func (t *Trigger) HasConditions() bool {
+	return len(t.ChatType) > 0 || t.Sender != nil || len(t.If) > 0 ||
+		len(t.Any) > 0 || len(t.All) > 0 || t.Not != nil
+}
+
 func (t *Trigger) validate() error {
 	types := t.Types()
-	if len(types) == 0 {
+	if len(types) == 0 && !t.HasConditions() {
 		return ErrEmptyTrigger
 	}
-	// message, callback, preCheckout, postCheckout, fallback could be combined in any combination
+	// message, editedMessage, channelPost, editedChannelPost, messageReaction,
+	// callback, preCheckout, postCheckout, inlineQuery, chosenInlineResult,
+	// myChatMember, chatMember, chatJoinRequest, pollAnswer, webAppData, schedule, delayed,
+	// contextExpired could not be combined with each other
 	// any type except fallback could be combined with context and state types
 	// fallback could not be combined with any other type
 	if len(types) > 1 && slices.Contains(types, TriggerTypeFallback) {
 		return fmt.Errorf("fallback with other triggers: %w", ErrInvalidTriggerCombination)
 	}
-	unmixable := []TriggerType{TriggerTypeMessage, TriggerTypeCallback, TriggerTypePreCheckout, TriggerTypePostCheckout}
+	if t.Fallback && t.HasConditions() {
+		return fmt.Errorf("fallback with conditions: %w", ErrInvalidTriggerCombination)
+	}
+	unmixable := []TriggerType{
+		TriggerTypeMessage, TriggerTypeEditedMessage, TriggerTypeChannelPost, TriggerTypeEditedChannelPost,
+		TriggerTypeMessageReaction, TriggerTypeCallback, TriggerTypePreCheckout, TriggerTypePostCheckout,
+		TriggerTypeInlineQuery, TriggerTypeChosenInlineResult,
+		TriggerTypeMyChatMember, TriggerTypeChatMember, TriggerTypeChatJoinRequest, TriggerTypePollAnswer,
+		TriggerTypeWebAppData, TriggerTypeSchedule, TriggerTypeDelayed, TriggerTypeContextExpired,
+	}
 	var unmixableCnt int
 	for _, u := range unmixable {
 		if slices.Contains(types, u) {
@@ -139,27 +290,111 @@ func (t *Trigger) validate() error {
 	if t.Message != nil {
 		errs = append(errs, t.Message.validate()...)
 	}
+	if t.EditedMessage != nil {
+		errs = append(errs, t.EditedMessage.validate()...)
+	}
+	if t.ChannelPost != nil {
+		errs = append(errs, t.ChannelPost.validate()...)
+	}
+	if t.EditedChannelPost != nil {
+		errs = append(errs, t.EditedChannelPost.validate()...)
+	}
 	if t.Callback != nil {
 		errs = append(errs, t.Callback.validate()...)
 	}
+	if t.MyChatMember != nil {
+		errs = append(errs, t.MyChatMember.validate()...)
+	}
+	if t.ChatMember != nil {
+		errs = append(errs, t.ChatMember.validate()...)
+	}
+	if t.Schedule != nil {
+		errs = append(errs, t.Schedule.validate()...)
+	}
+	for _, ct := range t.ChatType {
+		if !slices.Contains(chatTypes, ct) {
+			errs = append(errs, fmt.Errorf("unknown chat type %q", ct))
+		}
+	}
+	if t.Sender != nil {
+		errs = append(errs, t.Sender.validate()...)
+	}
 	if len(t.State) > 0 {
 		for _, sc := range t.State {
 			errs = append(errs, sc.validate()...)
 		}
 	}
+	for _, c := range t.If {
+		errs = append(errs, c.validate()...)
+	}
+	for _, sub := range t.Any {
+		errs = append(errs, sub.validateNested())
+	}
+	for _, sub := range t.All {
+		errs = append(errs, sub.validateNested())
+	}
+	if t.Not != nil {
+		errs = append(errs, t.Not.validateNested())
+	}
 	return errors.Join(errs...)
 }
 
+// validateNested validates trigger of any, all or not combinator,
+// such triggers could not be fallback, schedule, delayed or contextExpired.
+func (t *Trigger) validateNested() error {
+	if t.Fallback || t.Schedule != nil || t.Delayed != "" || t.ContextExpired != "" {
+		return fmt.Errorf("nested trigger (%s): %w", t.Types(), ErrInvalidTriggerCombination)
+	}
+	return t.validate()
+}
+
 type MessageTrigger struct {
 	Text    []string
 	Command string
+	// Pattern is a regular expression to match message text,
+	// named groups are available as `match.<name>` variables.
+	Pattern *Pattern
+	// Args are names of command arguments, each argument is available
+	// as `message.args.<name>` variable.
+	Args []string
+	// Payload is a regular expression to match command arguments,
+	// e.g. deep-link parameter of `/start` command.
+	Payload *Pattern
+
+	// Photo matches messages with photo.
+	Photo bool
+	// Document matches messages with document of specified mime types.
+	Document *DocumentTrigger
+	// Voice matches voice messages.
+	Voice bool
+	// Video matches messages with video.
+	Video bool
+	// Location matches messages with shared location.
+	Location bool
+	// Contact matches messages with shared contact.
+	Contact bool
+	// Shared matches messages with users or chat shared
+	// by keyboard button with this request ID.
+	Shared *int
+}
+
+// HasMedia checks if trigger matches media messages.
+func (t *MessageTrigger) HasMedia() bool {
+	return t.Photo || t.Document != nil || t.Voice || t.Video || t.Location || t.Contact || t.Shared != nil
 }
 
 func (t *MessageTrigger) validate() []error {
-	if len(t.Text) == 0 && t.Command == "" {
+	if len(t.Text) == 0 && t.Command == "" && t.Pattern == nil && !t.HasMedia() {
 		return []error{errors.New("empty message trigger")}
 	}
-	return []error{}
+	var errs []error
+	if t.Command == "" && len(t.Args) > 0 {
+		errs = append(errs, errors.New("message trigger args without command"))
+	}
+	if t.Command == "" && t.Payload != nil {
+		errs = append(errs, errors.New("message trigger payload without command"))
+	}
+	return errs
 }
 
 func (t *MessageTrigger) UnmarshalYAML(node *yaml.Node) error {
@@ -172,28 +407,321 @@ func (t *MessageTrigger) UnmarshalYAML(node *yaml.Node) error {
 		t.Text = s
 	case yaml.MappingNode:
 		schema := &struct {
-			Text    Strings `yaml:"text"`
-			Command string  `yaml:"command"`
+			Text    Strings  `yaml:"text"`
+			Command string   `yaml:"command"`
+			Pattern *Pattern `yaml:"pattern"`
+			Args    Strings  `yaml:"args"`
+			Payload *Pattern `yaml:"payload"`
+
+			Photo    bool             `yaml:"photo"`
+			Document *DocumentTrigger `yaml:"document"`
+			Voice    bool             `yaml:"voice"`
+			Video    bool             `yaml:"video"`
+			Location bool             `yaml:"location"`
+			Contact  bool             `yaml:"contact"`
+			Shared   *int             `yaml:"shared"`
 		}{}
 		if err := node.Decode(schema); err != nil {
 			return err
 		}
 		t.Text = schema.Text
 		t.Command = schema.Command
+		t.Pattern = schema.Pattern
+		t.Args = schema.Args
+		t.Payload = schema.Payload
+		t.Photo = schema.Photo
+		t.Document = schema.Document
+		t.Voice = schema.Voice
+		t.Video = schema.Video
+		t.Location = schema.Location
+		t.Contact = schema.Contact
+		t.Shared = schema.Shared
+	default:
+		return fmt.Errorf("unexpected node kind: %v", node.Kind)
+	}
+	return nil
+}
+
+// DocumentTrigger matches messages with document attachment.
+// It could be declared as `true` to match any document.
+type DocumentTrigger struct {
+	// Mime types of document, any if empty.
+	Mime Strings `yaml:"mime"`
+}
+
+func (t *DocumentTrigger) UnmarshalYAML(node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		if node.Value != "true" {
+			return fmt.Errorf("unexpected document trigger value: %q", node.Value)
+		}
+	case yaml.AliasNode:
+		return t.UnmarshalYAML(node.Alias)
+	case yaml.MappingNode:
+		var schema struct {
+			Mime Strings `yaml:"mime"`
+		}
+		if err := node.Decode(&schema); err != nil {
+			return err
+		}
+		t.Mime = schema.Mime
 	default:
 		return fmt.Errorf("unexpected node kind: %v", node.Kind)
 	}
 	return nil
 }
 
+// InlineTrigger matches inline mode updates: inline queries by query text
+// or chosen inline results by result ID. It matches any update
+// of its kind if pattern is not specified.
+type InlineTrigger struct {
+	Pattern *Pattern
+}
+
+func (t *InlineTrigger) UnmarshalYAML(node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		var p Pattern
+		if err := node.Decode(&p); err != nil {
+			return err
+		}
+		t.Pattern = &p
+	case yaml.AliasNode:
+		return t.UnmarshalYAML(node.Alias)
+	case yaml.MappingNode:
+		var schema struct {
+			Pattern *Pattern `yaml:"pattern"`
+		}
+		if err := node.Decode(&schema); err != nil {
+			return err
+		}
+		t.Pattern = schema.Pattern
+	default:
+		return fmt.Errorf("unexpected node kind: %v", node.Kind)
+	}
+	return nil
+}
+
+// ChatMemberTrigger matches chat member status updates by new status
+// and old status. Possible statuses are: creator, administrator, member,
+// restricted, left and kicked. It matches any status update if statuses are empty.
+type ChatMemberTrigger struct {
+	Status    Strings `yaml:"status"`
+	OldStatus Strings `yaml:"oldStatus"`
+}
+
+func (t *ChatMemberTrigger) UnmarshalYAML(node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		var s Strings
+		if err := node.Decode(&s); err != nil {
+			return err
+		}
+		t.Status = s
+	case yaml.AliasNode:
+		return t.UnmarshalYAML(node.Alias)
+	case yaml.MappingNode:
+		var schema struct {
+			Status    Strings `yaml:"status"`
+			OldStatus Strings `yaml:"oldStatus"`
+		}
+		if err := node.Decode(&schema); err != nil {
+			return err
+		}
+		t.Status = schema.Status
+		t.OldStatus = schema.OldStatus
+	default:
+		return fmt.Errorf("unexpected node kind: %v", node.Kind)
+	}
+	return nil
+}
+
+func (t *ChatMemberTrigger) validate() []error {
+	var errs []error
+	for _, s := range append(slices.Clone(t.Status), t.OldStatus...) {
+		if !slices.Contains(chatMemberStatuses, s) {
+			errs = append(errs, fmt.Errorf("unknown chat member status %q", s))
+		}
+	}
+	return errs
+}
+
+var chatMemberStatuses = []string{"creator", "administrator", "member", "restricted", "left", "kicked"}
+
+// ChatJoinRequestTrigger matches requests to join the chat.
+// It could be declared as `chatJoinRequest: true` or `chatJoinRequest: {}`.
+type ChatJoinRequestTrigger struct{}
+
+func (t *ChatJoinRequestTrigger) UnmarshalYAML(node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		var v bool
+		if err := node.Decode(&v); err != nil || !v {
+			return fmt.Errorf("unexpected chat join request trigger: %q", node.Value)
+		}
+	case yaml.AliasNode:
+		return t.UnmarshalYAML(node.Alias)
+	case yaml.MappingNode:
+		if len(node.Content) > 0 {
+			return errors.New("unexpected chat join request trigger options")
+		}
+	default:
+		return fmt.Errorf("unexpected node kind: %v", node.Kind)
+	}
+	return nil
+}
+
+// ReactionTrigger matches message reaction updates by new reaction emoji,
+// it matches any reaction if emoji list is empty. It could be declared
+// as `messageReaction: true`, emoji or list of emoji.
+type ReactionTrigger struct {
+	Emoji Strings `yaml:"emoji"`
+}
+
+func (t *ReactionTrigger) UnmarshalYAML(node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		if node.Value == "true" {
+			return nil
+		}
+		t.Emoji = Strings{node.Value}
+	case yaml.SequenceNode:
+		return node.Decode(&t.Emoji)
+	case yaml.AliasNode:
+		return t.UnmarshalYAML(node.Alias)
+	case yaml.MappingNode:
+		var schema struct {
+			Emoji Strings `yaml:"emoji"`
+		}
+		if err := node.Decode(&schema); err != nil {
+			return err
+		}
+		t.Emoji = schema.Emoji
+	default:
+		return fmt.Errorf("unexpected node kind: %v", node.Kind)
+	}
+	return nil
+}
+
+// WebAppDataTrigger matches data sent by Mini App from keyboard button.
+// It could be declared as `true` to match any button or a button text.
+type WebAppDataTrigger struct {
+	// Button text, any button if empty.
+	Button string `yaml:"button"`
+}
+
+func (t *WebAppDataTrigger) UnmarshalYAML(node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		if node.Value != "true" {
+			t.Button = node.Value
+		}
+	case yaml.AliasNode:
+		return t.UnmarshalYAML(node.Alias)
+	case yaml.MappingNode:
+		var schema struct {
+			Button string `yaml:"button"`
+		}
+		if err := node.Decode(&schema); err != nil {
+			return err
+		}
+		t.Button = schema.Button
+	default:
+		return fmt.Errorf("unexpected node kind: %v", node.Kind)
+	}
+	return nil
+}
+
+var chatTypes = []string{"private", "group", "supergroup", "channel"}
+
+// SenderCondition restricts trigger to updates from specified users
+// or chat administrators.
+type SenderCondition struct {
+	// IDs of allowed users.
+	IDs []int64 `yaml:"ids"`
+	// Usernames of allowed users.
+	Usernames Strings `yaml:"usernames"`
+	// Admin allows only chat administrators.
+	Admin bool `yaml:"admin"`
+}
+
+func (c *SenderCondition) validate() []error {
+	if len(c.IDs) == 0 && len(c.Usernames) == 0 && !c.Admin {
+		return []error{errors.New("empty sender condition")}
+	}
+	return nil
+}
+
+// ScheduleTrigger runs the handler by cron expression for the list of chats,
+// or for all known chats if the list is empty. Trigger state conditions
+// could be used to select chats by state.
+type ScheduleTrigger struct {
+	Cron  string  `yaml:"cron"`
+	Chats []int64 `yaml:"chats"`
+}
+
+func (t *ScheduleTrigger) UnmarshalYAML(node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		t.Cron = node.Value
+	case yaml.AliasNode:
+		return t.UnmarshalYAML(node.Alias)
+	case yaml.MappingNode:
+		var schema struct {
+			Cron  string  `yaml:"cron"`
+			Chats []int64 `yaml:"chats"`
+		}
+		if err := node.Decode(&schema); err != nil {
+			return err
+		}
+		t.Cron = schema.Cron
+		t.Chats = schema.Chats
+	default:
+		return fmt.Errorf("unexpected node kind: %v", node.Kind)
+	}
+	return nil
+}
+
+func (t *ScheduleTrigger) validate() []error {
+	if t.Cron == "" {
+		return []error{errors.New("empty schedule cron expression")}
+	}
+	if _, err := cron.ParseStandard(t.Cron); err != nil {
+		return []error{fmt.Errorf("invalid schedule cron expression %q: %w", t.Cron, err)}
+	}
+	return nil
+}
+
+// CallbackTrigger matches callback query data. Data matches exact value,
+// Template matches a value with `{name}` placeholders, e.g. `item:{id}`,
+// Prefix matches data by prefix, and Pattern matches data by regular expression.
 type CallbackTrigger struct {
-	Data string
+	Data     string
+	Template string
+	Prefix   string
+	Pattern  *Pattern
 }
 
 func (t *CallbackTrigger) validate() []error {
-	if t.Data == "" {
+	var cnt int
+	if t.Data != "" {
+		cnt++
+	}
+	if t.Template != "" {
+		cnt++
+	}
+	if t.Prefix != "" {
+		cnt++
+	}
+	if t.Pattern != nil {
+		cnt++
+	}
+	if cnt == 0 {
 		return []error{errors.New("empty callback trigger")}
 	}
+	if cnt > 1 {
+		return []error{errors.New("callback trigger should have only one of data, template, prefix or pattern")}
+	}
 	return []error{}
 }
 
@@ -205,31 +733,45 @@ func (ct *CallbackTrigger) UnmarshalYAML(node *yaml.Node) error {
 		return ct.UnmarshalYAML(node.Alias)
 	case yaml.MappingNode:
 		var schema struct {
-			Data string `yaml:"data"`
+			Data     string   `yaml:"data"`
+			Template string   `yaml:"template"`
+			Prefix   string   `yaml:"prefix"`
+			Pattern  *Pattern `yaml:"pattern"`
 		}
 		if err := node.Decode(&schema); err != nil {
 			return err
 		}
 		ct.Data = schema.Data
+		ct.Template = schema.Template
+		ct.Prefix = schema.Prefix
+		ct.Pattern = schema.Pattern
 	default:
 		return fmt.Errorf("unexpected node kind: %v", node.Kind)
 	}
 	return nil
 }
 
+// StateCondition checks state value by key: if it's present
+// or matches comparison operators.
 type StateCondition struct {
-	Key     string  `yaml:"key"`
-	Present OptBool `yaml:"present"`
-	Eq      string  `yaml:"eq"`
-	NEq     string  `yaml:"neq"`
+	Key        string  `yaml:"key"`
+	Present    OptBool `yaml:"present"`
+	Comparison `yaml:",inline"`
 }
 
 func (sc *StateCondition) validate() []error {
 	if sc.Key == "" {
 		return []error{errors.New("empty state condition key")}
 	}
-	if !sc.Present.Valid && sc.Eq == "" && sc.NEq == "" {
+	if !sc.Present.Valid && sc.Comparison.Empty() {
 		return []error{errors.New("empty state condition value")}
 	}
+	// present, eq and neq are checked exclusively for compatibility,
+	// so they can't be combined with other comparisons
+	rest := sc.Comparison
+	rest.Eq, rest.NEq = "", ""
+	if (sc.Present.Valid || sc.Eq != "" || sc.NEq != "") && !rest.Empty() {
+		return []error{fmt.Errorf("state condition %q combines present, eq or neq with other comparisons", sc.Key)}
+	}
 	return []error{}
 }
@@ -15,16 +15,30 @@ func _() {
 	_ = x[TriggerTypePostCheckout-5]
 	_ = x[TriggerTypeState-6]
 	_ = x[TriggerTypeFallback-7]
+	_ = x[TriggerTypeInlineQuery-8]
+	_ = x[TriggerTypeChosenInlineResult-9]
+	_ = x[TriggerTypeMyChatMember-10]
+	_ = x[TriggerTypeChatMember-11]
+	_ = x[TriggerTypeChatJoinRequest-12]
+	_ = x[TriggerTypeSchedule-13]
+	_ = x[TriggerTypeDelayed-14]
+	_ = x[TriggerTypeContextExpired-15]
+	_ = x[TriggerTypeEditedMessage-16]
+	_ = x[TriggerTypeChannelPost-17]
+	_ = x[TriggerTypeEditedChannelPost-18]
+	_ = x[TriggerTypeMessageReaction-19]
+	_ = x[TriggerTypePollAnswer-20]
+	_ = x[TriggerTypeWebAppData-21]
 }
 
-const _TriggerType_name = "TriggerTypeMessageTriggerTypeCallbackTriggerTypeContextTriggerTypePreCheckoutTriggerTypePostCheckoutTriggerTypeStateTriggerTypeFallback"
+const _TriggerType_name = "TriggerTypeMessageTriggerTypeCallbackTriggerTypeContextTriggerTypePreCheckoutTriggerTypePostCheckoutTriggerTypeStateTriggerTypeFallbackTriggerTypeInlineQueryTriggerTypeChosenInlineResultTriggerTypeMyChatMemberTriggerTypeChatMemberTriggerTypeChatJoinRequestTriggerTypeScheduleTriggerTypeDelayedTriggerTypeContextExpiredTriggerTypeEditedMessageTriggerTypeChannelPostTriggerTypeEditedChannelPostTriggerTypeMessageReactionTriggerTypePollAnswerTriggerTypeWebAppData"
 
-var _TriggerType_index = [...]uint8{0, 18, 37, 55, 77, 100, 116, 135}
+var _TriggerType_index = [...]uint16{0, 18, 37, 55, 77, 100, 116, 135, 157, 186, 209, 230, 256, 275, 293, 318, 342, 364, 392, 418, 439, 460}
 
 func (i TriggerType) String() string {
-	i -= 1
-	if i >= TriggerType(len(_TriggerType_index)-1) {
-		return "TriggerType(" + strconv.FormatInt(int64(i+1), 10) + ")"
+	idx := int(i) - 1
+	if i < 1 || idx >= len(_TriggerType_index)-1 {
+		return "TriggerType(" + strconv.FormatInt(int64(i), 10) + ")"
 	}
-	return _TriggerType_name[_TriggerType_index[i]:_TriggerType_index[i+1]]
+	return _TriggerType_name[_TriggerType_index[idx]:_TriggerType_index[idx+1]]
 }
@@ -1,6 +1,7 @@
 package spec
 
 import (
+	"regexp"
 	"strconv"
 
 	"github.com/pkg/errors"
@@ -106,3 +107,23 @@ func unmarshalOptYaml[T any](node *yaml.Node, parser func(string) (T, error)) (T
 		return zero, errors.Errorf("expected scalar or alias node, got %v", node.Kind)
 	}
 }
+
+// Pattern is a regular expression which is compiled on YAML decoding.
+type Pattern struct {
+	*regexp.Regexp
+}
+
+func (p *Pattern) UnmarshalYAML(node *yaml.Node) error {
+	src, err := unmarshalOptYaml(node, func(s string) (string, error) {
+		return s, nil
+	})
+	if err != nil {
+		return err
+	}
+	re, err := regexp.Compile(src)
+	if err != nil {
+		return errors.Wrapf(err, "compile pattern %q", src)
+	}
+	p.Regexp = re
+	return nil
+}
@@ -0,0 +1,188 @@
+package spec
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Warnings returns possible issues of valid specification,
+// e.g. handlers which can both reply to the same update.
+func (s *Spec) Warnings() []string {
+	if s.Bot == nil {
+		return nil
+	}
+	return s.Bot.Warnings()
+}
+
+// Warnings returns possible issues of valid bot specification.
+func (s *Bot) Warnings() []string {
+	var res []string
+	hs := s.Handlers
+	for i := range hs {
+		for j := i + 1; j < len(hs); j++ {
+			left, right := hs[i], hs[j]
+			if left.Trigger == nil || right.Trigger == nil {
+				continue
+			}
+			// handlers are executed by priority and then in order of declaration,
+			// only the final first handler prevents the second one
+			first := left
+			if right.Priority > left.Priority {
+				first = right
+			}
+			if first.Final {
+				continue
+			}
+			if triggersOverlap(left.Trigger, right.Trigger) {
+				res = append(res, fmt.Sprintf("handlers %s and %s can match the same update, "+
+					"set final with higher priority to the handler which should reply alone",
+					handlerName(i, left), handlerName(j, right)))
+			}
+		}
+	}
+	if len(s.Commands) > 0 {
+		res = append(res, s.missingCommands()...)
+	}
+	timeout := DefaultUpdateTimeout
+	if s.Config != nil && s.Config.UpdateTimeout != "" {
+		if d, err := time.ParseDuration(s.Config.UpdateTimeout); err == nil {
+			timeout = d
+		}
+	}
+	for i, h := range hs {
+		var pause time.Duration
+		for _, r := range h.Replies {
+			if d, err := time.ParseDuration(r.Pause); err == nil {
+				pause += d
+			}
+		}
+		if pause >= timeout {
+			res = append(res, fmt.Sprintf("handler %s pauses for %s, it exceeds update timeout %s, "+
+				"increase updateTimeout config", handlerName(i, h), pause, timeout))
+		}
+	}
+	return res
+}
+
+func handlerName(i int, h *Handler) string {
+	if h.ID != "" {
+		return fmt.Sprintf("%q", h.ID)
+	}
+	return fmt.Sprintf("#%d", i)
+}
+
+// triggersOverlap checks if message or callback triggers can match
+// the same update. It doesn't check nested triggers and value conditions,
+// so triggers with such conditions are considered as overlapping.
+func triggersOverlap(left, right *Trigger) bool {
+	if left.Context != right.Context {
+		return false
+	}
+	if statesExclusive(left.State, right.State) {
+		return false
+	}
+	if len(left.ChatType) > 0 && len(right.ChatType) > 0 &&
+		!slices.ContainsFunc(left.ChatType, func(s string) bool { return slices.Contains(right.ChatType, s) }) {
+		return false
+	}
+	switch {
+	case left.Message != nil && right.Message != nil:
+		return messagesOverlap(left.Message, right.Message)
+	case left.EditedMessage != nil && right.EditedMessage != nil:
+		return messagesOverlap(left.EditedMessage, right.EditedMessage)
+	case left.ChannelPost != nil && right.ChannelPost != nil:
+		return messagesOverlap(left.ChannelPost, right.ChannelPost)
+	case left.EditedChannelPost != nil && right.EditedChannelPost != nil:
+		return messagesOverlap(left.EditedChannelPost, right.EditedChannelPost)
+	case left.Callback != nil && right.Callback != nil:
+		return callbacksOverlap(left.Callback, right.Callback)
+	}
+	return false
+}
+
+func messagesOverlap(left, right *MessageTrigger) bool {
+	if left.Command != "" && left.Command == right.Command &&
+		left.Payload == nil && right.Payload == nil {
+		return true
+	}
+	for _, text := range left.Text {
+		if slices.Contains(right.Text, text) {
+			return true
+		}
+		if right.Pattern != nil && right.Pattern.MatchString(text) {
+			return true
+		}
+	}
+	if left.Pattern != nil {
+		for _, text := range right.Text {
+			if left.Pattern.MatchString(text) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func callbacksOverlap(left, right *CallbackTrigger) bool {
+	if left.Data != "" && left.Data == right.Data {
+		return true
+	}
+	return left.Prefix != "" && left.Prefix == right.Prefix
+}
+
+// statesExclusive checks if state conditions could not match
+// the same state, e.g. different `eq` values of the same key.
+func statesExclusive(left, right []StateCondition) bool {
+	for _, l := range left {
+		for _, r := range right {
+			if l.Key != r.Key {
+				continue
+			}
+			if l.Eq != "" && r.Eq != "" && l.Eq != r.Eq {
+				return true
+			}
+			if l.Eq != "" && r.Eq == "" && r.NEq == l.Eq || r.Eq != "" && l.Eq == "" && l.NEq == r.Eq {
+				return true
+			}
+			if l.Present.Valid && r.Present.Valid && l.Present.Value != r.Present.Value {
+				return true
+			}
+			if l.Present.Valid && !l.Present.Value && r.Eq != "" || r.Present.Valid && !r.Present.Value && l.Eq != "" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// missingCommands returns warnings for handler commands missing
+// from the commands menu.
+func (s *Bot) missingCommands() []string {
+	var res []string
+	var reported []string
+	for i, h := range s.Handlers {
+		if h.Trigger == nil || h.Trigger.Message == nil {
+			continue
+		}
+		cmds := []string{h.Trigger.Message.Command}
+		for _, text := range h.Trigger.Message.Text {
+			if cmd, ok := strings.CutPrefix(text, "/"); ok {
+				cmd, _, _ = strings.Cut(cmd, " ")
+				cmd, _, _ = strings.Cut(cmd, "@")
+				cmds = append(cmds, cmd)
+			}
+		}
+		for _, cmd := range cmds {
+			if cmd == "" || slices.Contains(reported, cmd) ||
+				slices.ContainsFunc(s.Commands, func(c *Command) bool { return c.Command == cmd }) {
+				continue
+			}
+			reported = append(reported, cmd)
+			res = append(res, fmt.Sprintf("handler %s command /%s is missing from commands menu",
+				handlerName(i, h), cmd))
+		}
+	}
+	return res
+}
@@ -11,7 +11,10 @@ import (
 	"go.uber.org/multierr"
 )
 
-var _ types.StateProvider = (*DB)(nil)
+var (
+	_ types.StateProvider = (*DB)(nil)
+	_ types.ChatLister    = (*DB)(nil)
+)
 
 type DB struct {
 	con   *sql.DB
@@ -102,3 +105,22 @@ func (db *DB) Update(ctx context.Context, uid types.ChatID, state types.State) e
 		return nil
 	})
 }
+
+// Chats returns all chats with state.
+func (db *DB) Chats(ctx context.Context) ([]types.ChatID, error) {
+	rows, err := db.con.QueryContext(ctx,
+		`SELECT DISTINCT chat_id FROM bot_state WHERE bot_id = $1`, db.botID)
+	if err != nil {
+		return nil, errors.Wrap(err, "query chats")
+	}
+	defer rows.Close()
+	var res []types.ChatID
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, errors.Wrap(err, "scan chat")
+		}
+		res = append(res, types.ChatID(id))
+	}
+	return res, errors.Wrap(rows.Err(), "read chats")
+}
@@ -7,7 +7,10 @@ import (
 	"github.com/g4s8/openbots/pkg/types"
 )
 
-var _ types.StateProvider = (*Memory)(nil)
+var (
+	_ types.StateProvider = (*Memory)(nil)
+	_ types.ChatLister    = (*Memory)(nil)
+)
 
 type Memory struct {
 	global map[string]string
@@ -70,3 +73,15 @@ func (m *Memory) Update(_ context.Context, user types.ChatID, state types.State)
 	}
 	return nil
 }
+
+// Chats returns all chats with state.
+func (m *Memory) Chats(_ context.Context) ([]types.ChatID, error) {
+	m.mux.RLock()
+	defer m.mux.RUnlock()
+
+	res := make([]types.ChatID, 0, len(m.users))
+	for id := range m.users {
+		res = append(res, id)
+	}
+	return res, nil
+}
@@ -51,3 +51,21 @@ func TestMemory(t *testing.T) {
 	assert.That("second-get-one", state, matchGet(assert, "one", false, ""))
 	assert.That("second-get-two", state, matchGet(assert, "two", false, ""))
 }
+
+func TestMemoryChats(t *testing.T) {
+	ctx := context.Background()
+	assert := m.Assert(t)
+
+	mem := NewMemory(nil)
+	state := NewUserState()
+	_ = mem.Load(ctx, types.ChatID(1), state)
+	state.Set("foo", "bar")
+	_ = mem.Update(ctx, types.ChatID(1), state)
+	state.reset()
+	_ = mem.Load(ctx, types.ChatID(2), state)
+	_ = mem.Update(ctx, types.ChatID(2), state)
+
+	chats, err := mem.Chats(ctx)
+	assert.That("no-error", err, m.Nil())
+	assert.That("chats", chats, m.AllOf(m.LenIs(1), m.HasItemEq(types.ChatID(1))))
+}
@@ -2,6 +2,7 @@ package types
 
 import (
 	ctx "context"
+	"time"
 )
 
 type ContextProvider interface {
@@ -19,3 +20,31 @@ type Context interface {
 	// Check context value.
 	Check(ctx.Context, string) (bool, error)
 }
+
+// ExpiringContext is an optional interface of Context
+// to set context value which expires after TTL.
+type ExpiringContext interface {
+	// SetWithTTL sets context value with time to live.
+	SetWithTTL(ctx.Context, string, time.Duration) error
+}
+
+// ExpiredContext is a context value which was expired.
+type ExpiredContext struct {
+	ChatID ChatID
+	Value  string
+}
+
+// ContextExpirer is an optional interface of ContextProvider
+// to clear expired contexts.
+type ContextExpirer interface {
+	// Expire removes all contexts expired before the time and returns them.
+	Expire(ctx.Context, time.Time) ([]ExpiredContext, error)
+}
+
+// ChatContextExpirer is an optional interface of ContextProvider
+// to clear expired context of one chat.
+type ChatContextExpirer interface {
+	// ExpireChat removes context of the chat if it was expired before the time
+	// and returns it, ok is false if the context is not expired.
+	ExpireChat(ctx.Context, ChatID, time.Time) (exp ExpiredContext, ok bool, err error)
+}
@@ -0,0 +1,23 @@
+package types
+
+import (
+	"context"
+	"time"
+)
+
+// Job is a delayed event of the chat.
+type Job struct {
+	ChatID ChatID
+	Event  string
+	At     time.Time
+}
+
+// JobsProvider keeps delayed jobs.
+type JobsProvider interface {
+	// Add new job.
+	Add(context.Context, Job) error
+	// Cancel all pending jobs of the chat with event.
+	Cancel(context.Context, ChatID, string) error
+	// Due removes and returns all jobs scheduled before the time.
+	Due(context.Context, time.Time) ([]Job, error)
+}
@@ -0,0 +1,39 @@
+package types
+
+import (
+	"context"
+	"errors"
+)
+
+// Poll is a poll sent by the bot.
+type Poll struct {
+	// ID is a telegram poll ID.
+	ID        string
+	ChatID    ChatID
+	MessageID int
+	// Name of the poll from bot specification.
+	Name    string
+	Options []string
+	// CorrectOption is an index of correct option of quiz,
+	// it's -1 for regular polls.
+	CorrectOption int
+}
+
+// Quiz checks if poll is a quiz.
+func (p Poll) Quiz() bool {
+	return p.CorrectOption >= 0
+}
+
+// ErrPollNotFound is returned when poll is not registered.
+var ErrPollNotFound = errors.New("poll not found")
+
+// PollsProvider keeps polls sent by the bot to map poll answers
+// to the chat and the handler.
+type PollsProvider interface {
+	// Add sent poll.
+	Add(context.Context, Poll) error
+	// Get poll by ID or return ErrPollNotFound.
+	Get(context.Context, string) (Poll, error)
+	// Remove closed poll by ID, it doesn't fail if poll is not registered.
+	Remove(context.Context, string) error
+}
@@ -25,6 +25,12 @@ type StateProvider interface {
 	Update(context.Context, ChatID, State) error
 }
 
+// ChatLister is an optional interface of StateProvider
+// to list all chats with state.
+type ChatLister interface {
+	Chats(context.Context) ([]ChatID, error)
+}
+
 type StateOp interface {
 	Apply(State, ...func(string) string) error
 }